@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/notify"
+)
+
+// version is set at build time: -ldflags '-X main.version=...'
+var version = "dev"
+
+// command is a check_sync subcommand, run gets arguments following the command name
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+// commands lists all subcommands, the first one is the default
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "check", usage: "compare landscape with DevStats and notify about findings, or check at a past date (-at, -from/-to)", run: check},
+		{name: "explain", usage: "show a project side by side in all sources, applying exceptions and resulting findings", run: explain},
+		{name: "fix", usage: "print edits making devstats-helm projects.yaml agree with devstats projects.yaml", run: fix},
+		{name: "serve", usage: "run checks on a schedule and serve the latest report over HTTP", run: serve},
+		{name: "exceptions", usage: "list, show, add and remove exceptions: known and accepted differences", run: exceptions},
+		{name: "history", usage: "show findings trend and when findings were first and last seen", run: history},
+		{name: "version", usage: "print version", run: printVersion},
+		{name: "help", usage: "show this help", run: help},
+	}
+}
+
+// usageError is an invalid command line, it is printed and makes check_sync exit with code 2
+type usageError struct {
+	error
+}
+
+// exitError makes check_sync exit with a given code without printing anything more
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: check_sync [command] [flags]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\ncheck is the default command. Run 'check_sync <command> -h' to see command flags,\n")
+	fmt.Fprintf(os.Stderr, "flags default to environment variables given in parentheses.\n")
+}
+
+func help(args []string) error {
+	if len(args) > 0 {
+		return usageError{fmt.Errorf("unexpected arguments: %s", strings.Join(args, " "))}
+	}
+	usage()
+	return nil
+}
+
+func printVersion(args []string) error {
+	fs := newFlagSet("version", "")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("check_sync %s (%s %s/%s)\n", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return nil
+}
+
+// newFlagSet returns flag set of a given command, args describes its positional arguments
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: check_sync %s [flags]%s\n\nflags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses command flags, unknown flags and more than maxArgs positional arguments are errors
+// Errors are already printed together with command usage
+func parseFlags(fs *flag.FlagSet, args []string, maxArgs int) error {
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return err
+	}
+	if err != nil {
+		return exitError(2)
+	}
+	// Values defaulting to environment variables are not checked by Parse
+	fs.VisitAll(func(f *flag.Flag) {
+		v, ok := f.Value.(*choiceValue)
+		if ok && err == nil {
+			err = v.Set(v.String())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid value \"%s\" for flag -%s: %v\n", v.String(), f.Name, err)
+			}
+		}
+	})
+	if err != nil {
+		fs.Usage()
+		return exitError(2)
+	}
+	if fs.NArg() > maxArgs {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %s\n", strings.Join(fs.Args()[maxArgs:], " "))
+		fs.Usage()
+		return exitError(2)
+	}
+	return nil
+}
+
+// choiceValue is a string flag value limited to given choices
+type choiceValue struct {
+	value   *string
+	choices []string
+}
+
+// newChoiceValue returns a choice flag value set to a given default, it is validated when flags are parsed
+func newChoiceValue(p *string, def string, choices []string) *choiceValue {
+	*p = def
+	return &choiceValue{value: p, choices: choices}
+}
+
+func (c *choiceValue) String() string {
+	if c.value == nil {
+		return ""
+	}
+	return *c.value
+}
+
+func (c *choiceValue) Set(value string) error {
+	for _, choice := range c.choices {
+		if value == choice {
+			*c.value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of: %s", strings.Join(c.choices, ", "))
+}
+
+// secretValue is a string flag value that is never printed, so its default (from environment) is not shown in usage
+type secretValue struct {
+	value *string
+}
+
+// newSecretValue returns a secret flag value set to a given default
+func newSecretValue(p *string, def string) *secretValue {
+	*p = def
+	return &secretValue{value: p}
+}
+
+func (s *secretValue) String() string {
+	return ""
+}
+
+func (s *secretValue) Set(value string) error {
+	*s.value = value
+	return nil
+}
+
+// envOr returns value of a given environment variable or a default when it is not set
+func envOr(name, def string) string {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// checkOptions holds settings of a sync check, flags default to environment variables
+type checkOptions struct {
+	paths       loader.Paths
+	exceptions  string
+	severity    string
+	state       string
+	historyDB   string
+	metrics     string
+	emailTo     string
+	skipEmail   bool
+	debug       bool
+	allArchived string
+	notify      notify.Config
+	owners      string
+	clones      loader.Clones
+	blamePull   bool
+}
+
+// registerExceptions adds the exceptions store flag
+func (o *checkOptions) registerExceptions(fs *flag.FlagSet) {
+	fs.StringVar(&o.exceptions, "exceptions", envOr("EXCEPTIONS_YAML_PATH", "exceptions.yaml"), "exceptions store url|path, edited only as a local file (EXCEPTIONS_YAML_PATH)")
+}
+
+// registerInputs adds flags setting input locations and checker options
+func (o *checkOptions) registerInputs(fs *flag.FlagSet) {
+	o.registerExceptions(fs)
+	def := loader.PathsFromEnv()
+	fs.StringVar(&o.paths.Landscape, "landscape", def.Landscape, "landscape.yml, landscape2 data.yml or full.json url|path (LANDSCAPE_YAML_PATH)")
+	fs.StringVar(&o.paths.Projects, "projects", def.Projects, "devstats projects.yaml url|path (PROJECTS_YAML_PATH)")
+	fs.StringVar(&o.paths.Projects2, "helm-projects", def.Projects2, "devstats-docker-images devstats-helm/projects.yaml url|path (DOCKER_PROJECTS_YAML_PATH)")
+	fs.StringVar(&o.paths.CLOMonitor, "clomonitor", def.CLOMonitor, "optional CLOMonitor data/cncf.yaml url|path compared with devstats projects.yaml (CLOMONITOR_YAML_PATH)")
+	fs.StringVar(&o.paths.TOC, "toc", def.TOC, "optional CNCF TOC projects list (markdown tables) url|path checked against devstats and landscape (TOC_MD_PATH)")
+	fs.StringVar(&o.paths.Maintainers, "maintainers", def.Maintainers, "optional CNCF project maintainers list (csv) url|path checked against devstats (MAINTAINERS_CSV_PATH)")
+	fs.StringVar(&o.paths.HelmValues, "helm-values", def.HelmValues, "optional devstats-helm values.yaml url|path, its index ranges are checked against devstats-helm projects.yaml (HELM_VALUES_YAML_PATH)")
+	fs.StringVar(&o.paths.Website, "website", def.Website, "optional DevStats index page url|path or local cncf/devstats checkout, checked against devstats projects.yaml (WEBSITE_HTML_PATH)")
+	fs.StringVar(&o.paths.AllRepoGroups, "all-repo-groups", def.AllRepoGroups, "optional \"All CNCF\" repo groups sql url|path or local cncf/devstats checkout, checked against devstats projects.yaml (ALL_REPO_GROUPS_PATH)")
+	fs.Var(newChoiceValue(&o.allArchived, envOr("ALL_ARCHIVED", compare.AllArchivedInclude), compare.AllArchivedModes), "all-archived", "how archived projects are checked in \"All CNCF\" repo groups, `mode` is "+strings.Join(compare.AllArchivedModes, "|")+" (ALL_ARCHIVED)")
+	fs.StringVar(&o.severity, "severity", os.Getenv("SEVERITY_YAML_PATH"), "severity policy url|path, default severity.yaml when present (SEVERITY_YAML_PATH)")
+	fs.BoolVar(&o.debug, "debug", os.Getenv("DBG") != "", "print details of projects missing in DevStats (DBG)")
+}
+
+// register adds all flags of a sync check run
+func (o *checkOptions) register(fs *flag.FlagSet) {
+	o.registerInputs(fs)
+	fs.StringVar(&o.state, "state", envOr("STATE_PATH", "check_sync_state.json"), "where fingerprints of the last run's findings are stored (STATE_PATH)")
+	fs.StringVar(&o.historyDB, "history-db", os.Getenv("HISTORY_DB_PATH"), "history database path, empty disables history (HISTORY_DB_PATH)")
+	fs.StringVar(&o.metrics, "metrics-textfile", os.Getenv("METRICS_TEXTFILE"), "Prometheus textfile collector file to write metrics to (METRICS_TEXTFILE)")
+	fs.StringVar(&o.emailTo, "email-to", os.Getenv("EMAIL_TO"), "comma separated global digest recipients, default are admins from owners.yaml (EMAIL_TO)")
+	fs.BoolVar(&o.skipEmail, "skip-email", os.Getenv("SKIP_EMAIL") != "", "disable email notifier (SKIP_EMAIL)")
+	o.registerNotify(fs)
+	o.registerClones(fs)
+}
+
+// registerNotify adds flags setting notifiers, ownership map and mail transport
+func (o *checkOptions) registerNotify(fs *flag.FlagSet) {
+	def := notify.ConfigFromEnv()
+	fs.StringVar(&o.notify.Notifiers, "notifiers", def.Notifiers, "comma separated notifiers: email, slack, webhook, github (NOTIFIERS)")
+	fs.StringVar(&o.owners, "owners", os.Getenv("OWNERS_YAML_PATH"), "ownership map url|path, default owners.yaml when present (OWNERS_YAML_PATH)")
+	fs.Var(newChoiceValue(&o.notify.Mail.Transport, def.Mail.Transport, notify.MailTransports), "mail-transport", "how emails are sent, `transport` is "+strings.Join(notify.MailTransports, "|")+" (MAIL_TRANSPORT)")
+	fs.StringVar(&o.notify.Mail.SendmailPath, "sendmail-path", def.Mail.SendmailPath, "sendmail binary used by sendmail transport (SENDMAIL_PATH)")
+	fs.StringVar(&o.notify.Mail.SMTPHost, "smtp-host", def.Mail.SMTPHost, "SMTP server host (SMTP_HOST)")
+	fs.StringVar(&o.notify.Mail.SMTPPort, "smtp-port", def.Mail.SMTPPort, "SMTP server port (SMTP_PORT)")
+	fs.StringVar(&o.notify.Mail.SMTPUser, "smtp-user", def.Mail.SMTPUser, "SMTP user, empty disables AUTH (SMTP_USER)")
+	fs.Var(newSecretValue(&o.notify.Mail.SMTPPassword, def.Mail.SMTPPassword), "smtp-password", "SMTP `password`, prefer the environment variable as flags are visible to other users (SMTP_PASSWORD)")
+	fs.Var(newChoiceValue(&o.notify.Mail.StartTLS, def.Mail.StartTLS, notify.StartTLSModes), "smtp-starttls", "SMTP STARTTLS `mode` is "+strings.Join(notify.StartTLSModes, "|")+", auto uses it when supported (SMTP_STARTTLS)")
+	fs.BoolVar(&o.notify.Mail.Insecure, "smtp-insecure-skip-verify", def.Mail.Insecure, "do not verify SMTP server certificate (SMTP_INSECURE_SKIP_VERIFY)")
+}
+
+// registerClones adds flags setting local clones used by time travel checks and blame
+func (o *checkOptions) registerClones(fs *flag.FlagSet) {
+	def := loader.ClonesFromEnv()
+	fs.StringVar(&o.clones.Landscape, "landscape-repo", def.Landscape, "local clone of cncf/landscape (LANDSCAPE_REPO_PATH)")
+	fs.StringVar(&o.clones.Devstats, "devstats-repo", def.Devstats, "local clone of cncf/devstats (DEVSTATS_REPO_PATH)")
+	fs.StringVar(&o.clones.DevstatsDockerImages, "devstats-docker-images-repo", def.DevstatsDockerImages, "local clone of cncf/devstats-docker-images (DEVSTATS_DOCKER_IMAGES_REPO_PATH)")
+	fs.BoolVar(&o.blamePull, "blame-pull", os.Getenv("BLAME_PULL") != "", "git pull local clones before blaming new findings (BLAME_PULL)")
+}
+
+// run runs a command given by command line arguments (without program name) and returns the exit code
+func run(args []string) int {
+	cmd := commands[0]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		found := false
+		for _, c := range commands {
+			if c.name == args[0] {
+				cmd, found = c, true
+				break
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+			usage()
+			return 2
+		}
+		args = args[1:]
+	}
+	err := cmd.run(args)
+	var (
+		uErr usageError
+		eErr exitError
+	)
+	switch {
+	case err == nil:
+	case err == flag.ErrHelp:
+	case errors.As(err, &uErr):
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	case errors.As(err, &eErr):
+		return int(eErr)
+	default:
+		fmt.Printf("error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
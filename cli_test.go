@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+// TestAllArchivedFlag makes sure invalid -all-archived values, also from ALL_ARCHIVED, are usage errors (exit code 2)
+func TestAllArchivedFlag(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		env  string
+		args []string
+		want string
+		err  error
+	}{
+		{name: "default", want: "include"},
+		{name: "env", env: "ignore", want: "ignore"},
+		{name: "flag overrides env", env: "ignore", args: []string{"-all-archived", "exclude"}, want: "exclude"},
+		{name: "invalid flag", args: []string{"-all-archived", "skip"}, err: exitError(2)},
+		{name: "invalid env", env: "bogus", err: exitError(2)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ALL_ARCHIVED", tc.env)
+			o := &checkOptions{}
+			fs := newFlagSet("check", "")
+			o.register(fs)
+			err := parseFlags(fs, tc.args, 0)
+			if err != tc.err {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if err == nil && o.allArchived != tc.want {
+				t.Errorf("got %s, want %s", o.allArchived, tc.want)
+			}
+		})
+	}
+}
+
+// clearEnv unsets environment variables that flags of check default to
+func clearEnv(t *testing.T) {
+	for _, env := range []string{
+		"NOTIFIERS", "OWNERS_YAML_PATH", "MAIL_TRANSPORT", "SENDMAIL_PATH", "SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASSWORD",
+		"SMTP_STARTTLS", "SMTP_INSECURE_SKIP_VERIFY", "LANDSCAPE_REPO_PATH", "DEVSTATS_REPO_PATH", "DEVSTATS_DOCKER_IMAGES_REPO_PATH",
+		"BLAME_PULL", "ALL_ARCHIVED", "EMAIL_TO", "SKIP_EMAIL", "HISTORY_DB_PATH", "METRICS_TEXTFILE", "SEVERITY_YAML_PATH",
+		"CLOMONITOR_YAML_PATH", "TOC_MD_PATH", "MAINTAINERS_CSV_PATH", "HELM_VALUES_YAML_PATH", "WEBSITE_HTML_PATH", "ALL_REPO_GROUPS_PATH",
+	} {
+		t.Setenv(env, "")
+	}
+}
+
+// TestNotifyFlags checks notifier, mail transport and local clones flags and their environment defaults
+func TestNotifyFlags(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("NOTIFIERS", "slack,github")
+	t.Setenv("SMTP_HOST", "mail.example.com")
+	t.Setenv("SMTP_PASSWORD", "s3cr3t")
+	t.Setenv("DEVSTATS_REPO_PATH", "/src/devstats")
+	t.Setenv("BLAME_PULL", "1")
+	o := &checkOptions{}
+	fs := newFlagSet("check", "")
+	o.register(fs)
+	err := parseFlags(fs, []string{"-mail-transport", "smtp", "-smtp-port", "587", "-smtp-starttls", "require", "-owners", "team.yaml", "-landscape-repo", "/src/landscape"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mail := o.notify.Mail
+	if o.notify.Notifiers != "slack,github" || mail.Transport != "smtp" || mail.SMTPHost != "mail.example.com" || mail.SMTPPort != "587" ||
+		mail.SMTPPassword != "s3cr3t" || mail.StartTLS != "require" || mail.SendmailPath != "sendmail" || o.owners != "team.yaml" {
+		t.Errorf("unexpected notify options: %+v, owners '%s'", o.notify, o.owners)
+	}
+	if o.clones != (loader.Clones{Landscape: "/src/landscape", Devstats: "/src/devstats"}) || !o.blamePull {
+		t.Errorf("unexpected clones %+v, pull %v", o.clones, o.blamePull)
+	}
+	// Password from environment is not shown in usage
+	buf := &bytes.Buffer{}
+	fs.SetOutput(buf)
+	fs.PrintDefaults()
+	if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), "-smtp-password") {
+		t.Errorf("unexpected usage:\n%s", buf.String())
+	}
+	for _, tc := range []struct {
+		env, value string
+		args       []string
+	}{
+		{args: []string{"-mail-transport", "pigeon"}},
+		{args: []string{"-smtp-starttls", "maybe"}},
+		{env: "MAIL_TRANSPORT", value: "pigeon"},
+		{env: "SMTP_STARTTLS", value: "maybe"},
+		{args: []string{"-blame-pull=maybe"}},
+	} {
+		if tc.env != "" {
+			t.Setenv(tc.env, tc.value)
+		}
+		o := &checkOptions{}
+		fs := newFlagSet("check", "")
+		fs.SetOutput(ioutil.Discard)
+		o.register(fs)
+		err := parseFlags(fs, tc.args, 0)
+		if err != exitError(2) {
+			t.Errorf("%s=%s %v: got error %v, want exit code 2", tc.env, tc.value, tc.args, err)
+		}
+		if tc.env != "" {
+			t.Setenv(tc.env, "")
+		}
+	}
+}
+
+// TestRun checks subcommand parsing and exit codes: 2 for usage errors, 1 for failures and error findings
+func TestRun(t *testing.T) {
+	clearEnv(t)
+	store, inputs := testInputs(t)
+	state := filepath.Join(t.TempDir(), "state.json")
+	inSync := filepath.Join("pkg", "compare", "testdata", "in-sync")
+	checkArgs := append([]string{"-state", state, "-notifiers", "", "-owners", ""}, inputs...)
+	for _, tc := range []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "unknown command", args: []string{"sync"}, code: 2},
+		{name: "help", args: []string{"help"}},
+		{name: "help with arguments", args: []string{"help", "check"}, code: 2},
+		{name: "command help", args: []string{"fix", "-h"}},
+		{name: "default command help", args: []string{"-h"}},
+		{name: "version", args: []string{"version"}},
+		{name: "version with arguments", args: []string{"version", "1"}, code: 2},
+		{name: "unknown flag", args: []string{"check", "-color"}, code: 2},
+		{name: "default command unknown flag", args: []string{"-color"}, code: 2},
+		{name: "unexpected argument", args: []string{"check", "now"}, code: 2},
+		{name: "explain without project", args: append([]string{"explain"}, inputs...), code: 2},
+		{name: "explain", args: append(append([]string{"explain"}, inputs...), "pinned")},
+		{name: "explain unknown project", args: append(append([]string{"explain"}, inputs...), "nothing"), code: 1},
+		{name: "history without database", args: []string{"history"}, code: 2},
+		{name: "exceptions without command", args: []string{"exceptions"}, code: 2},
+		{name: "exceptions list", args: []string{"exceptions", "list", "-exceptions", store}},
+		{name: "at with from", args: []string{"check", "-at", "2024-01-01", "-from", "2023-01-01"}, code: 2},
+		{name: "to without from", args: []string{"check", "-to", "2024-01-01"}, code: 2},
+		{name: "at without clones", args: append([]string{"check", "-at", "2024-01-01"}, inputs...), code: 1},
+		{name: "serve invalid schedule", args: []string{"serve", "-schedule", "never"}, code: 2},
+		{name: "check with error findings", args: append([]string{"check"}, checkArgs...), code: 1},
+		{name: "default command", args: checkArgs, code: 1},
+		{name: "check in sync", args: append(append([]string{"check"}, checkArgs...),
+			"-landscape", filepath.Join(inSync, "landscape.yml"),
+			"-projects", filepath.Join(inSync, "projects.yaml"),
+			"-helm-projects", filepath.Join(inSync, "helm-projects.yaml"),
+		)},
+		{name: "check with unknown notifier", args: append(append([]string{"check"}, checkArgs...), "-notifiers", "pager"), code: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			code := run(tc.args)
+			if code != tc.code {
+				t.Errorf("got exit code %d, want %d", code, tc.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// exceptionsCommands lists exceptions subcommands
+var exceptionsCommands []command
+
+func init() {
+	exceptionsCommands = []command{
+		{name: "list", usage: "list exceptions with their reasons", run: exceptionsList},
+		{name: "show", usage: "show an exception and findings it suppresses", run: exceptionsShow},
+		{name: "add", usage: "add an exception for a finding (fingerprint or project and field), or of a given type", run: exceptionsAdd},
+		{name: "remove", usage: "remove an exception given by ID (type/project) or project and field", run: exceptionsRemove},
+	}
+}
+
+// fieldTypes maps finding fields to exception types that can apply to them
+var fieldTypes = map[string][]string{
+	"name":       {normalize.TypeIgnoreMissing, normalize.TypeSkip, normalize.TypeRename},
+	"repo":       {normalize.TypeIgnoreRepo},
+	"join":       {normalize.TypeIgnoreJoinDate},
+	"incubating": {normalize.TypeIgnoreIncubatingDate},
+	"graduated":  {normalize.TypeIgnoreGraduatedDate},
+	"status":     {normalize.TypeIgnoreStatus},
+}
+
+func exceptionsUsage() {
+	fmt.Fprintf(os.Stderr, "usage: check_sync exceptions <command> [flags]\n\ncommands:\n")
+	for _, cmd := range exceptionsCommands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nexception types: %s\n", strings.Join(normalize.Types, ", "))
+}
+
+// exceptions implements the exceptions command: manages the exceptions store
+func exceptions(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		exceptionsUsage()
+		if len(args) == 0 {
+			return exitError(2)
+		}
+		return flag.ErrHelp
+	}
+	for _, cmd := range exceptionsCommands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown exceptions command '%s'\n\n", args[0])
+	exceptionsUsage()
+	return exitError(2)
+}
+
+// loadStore reads the exceptions store, when edit is set it must be a local file
+func loadStore(o *checkOptions, edit bool) (*normalize.Store, error) {
+	if edit && (strings.HasPrefix(o.exceptions, "http://") || strings.HasPrefix(o.exceptions, "https://")) {
+		return nil, usageError{fmt.Errorf("cannot edit exceptions store at '%s', use a local file", o.exceptions)}
+	}
+	store, err := loader.LoadExceptions(o.exceptions)
+	if err != nil {
+		return nil, fmt.Errorf("loading exceptions: %v", err)
+	}
+	return store, nil
+}
+
+// describe returns an exception's expected values (if any) and its reason
+func describe(typ string, e normalize.Exception) string {
+	switch typ {
+	case normalize.TypeRename:
+		return fmt.Sprintf("landscape '%s': %s", e.Landscape, e.Reason)
+	case normalize.TypeIgnoreRepo:
+		return fmt.Sprintf("landscape '%s', devstats '%s': %s", e.Landscape, e.DevStats, e.Reason)
+	}
+	return e.Reason
+}
+
+// suppressedBy returns findings that are reported when a given exception is removed from the store
+func suppressedBy(c *compare.Checker, in *loader.Inputs, store *normalize.Store, typ, project string) ([]model.Finding, error) {
+	e, err := store.Get(typ, project)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	saved := *e
+	with := c.Compare(in)
+	_, _ = store.Remove(typ, project)
+	without := *c
+	without.Exceptions = store.Exceptions()
+	_, err = store.Put(typ, saved)
+	if err != nil {
+		return nil, err
+	}
+	reported := make(map[string]struct{})
+	for _, f := range with.Findings {
+		reported[f.Fingerprint] = struct{}{}
+	}
+	suppressed := []model.Finding{}
+	for _, f := range without.Compare(in).Findings {
+		_, ok := reported[f.Fingerprint]
+		if !ok && f.Kind != model.KindStatusCount {
+			suppressed = append(suppressed, f)
+		}
+	}
+	return suppressed, nil
+}
+
+// exceptionsList implements exceptions list
+func exceptionsList(args []string) error {
+	o := &checkOptions{}
+	fs := newFlagSet("exceptions list", "")
+	o.registerExceptions(fs)
+	typ := fs.String("type", "", "list only exceptions of a given type")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	store, err := loadStore(o, false)
+	if err != nil {
+		return err
+	}
+	n := 0
+	store.Each(func(t string, e normalize.Exception) {
+		if *typ != "" && t != *typ {
+			return
+		}
+		fmt.Printf("%s: %s\n", normalize.ID(t, e.Project), describe(t, e))
+		n++
+	})
+	fmt.Printf("%d exceptions\n", n)
+	return nil
+}
+
+// exceptionsShow implements exceptions show
+func exceptionsShow(args []string) error {
+	o := &checkOptions{}
+	fs := newFlagSet("exceptions show", " <type/project>")
+	o.registerInputs(fs)
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return usageError{fmt.Errorf("exception ID (type/project) is required")}
+	}
+	typ, project, err := normalize.ParseID(fs.Arg(0))
+	if err != nil {
+		return usageError{err}
+	}
+	store, err := loadStore(o, false)
+	if err != nil {
+		return err
+	}
+	c, err := newCheckerWith(o, store)
+	if err != nil {
+		return err
+	}
+	e, err := store.Get(typ, project)
+	if err != nil {
+		return usageError{err}
+	}
+	if e == nil {
+		return fmt.Errorf("exception '%s' not found", normalize.ID(typ, project))
+	}
+	fmt.Printf("%s\n", normalize.ID(typ, e.Project))
+	if e.Landscape != "" || typ == normalize.TypeIgnoreRepo {
+		fmt.Printf("  landscape: %s\n", e.Landscape)
+	}
+	if e.DevStats != "" || typ == normalize.TypeIgnoreRepo {
+		fmt.Printf("  devstats: %s\n", e.DevStats)
+	}
+	fmt.Printf("  reason: %s\n", e.Reason)
+	in, err := loader.Load(loader.ReadDefault, o.paths)
+	if err != nil {
+		return err
+	}
+	suppressed, err := suppressedBy(c, in, store, typ, project)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("suppresses:\n")
+	if len(suppressed) == 0 {
+		fmt.Printf("  nothing, the exception is no longer needed\n")
+	}
+	for _, f := range suppressed {
+		fmt.Printf("  %s (%s %s)\n", strings.TrimSuffix(f.Message, "\n"), f.Kind, f.Fingerprint)
+	}
+	return nil
+}
+
+// findingException returns exception type and entry suppressing a given finding, filled with current values
+func findingException(r *compare.Result, f model.Finding) (string, normalize.Exception, error) {
+	e := normalize.Exception{Project: f.Project}
+	switch f.Kind {
+	case model.KindMissingDevstats:
+		return normalize.TypeIgnoreMissing, e, nil
+	case model.KindMissingLandscape:
+		// Skip uses DevStats keys, namesMapping (Aliases) maps full names to them
+		key, ok := r.Aliases[f.Project]
+		if ok {
+			e.Project = key
+		}
+		return normalize.TypeSkip, e, nil
+	case model.KindRepo, model.KindRepoExceptionLandscape, model.KindRepoExceptionDevstats:
+		e.Landscape, e.DevStats = r.Repos(f.Project)
+		return normalize.TypeIgnoreRepo, e, nil
+	case model.KindJoin:
+		return normalize.TypeIgnoreJoinDate, e, nil
+	case model.KindIncubating:
+		return normalize.TypeIgnoreIncubatingDate, e, nil
+	case model.KindGraduated:
+		return normalize.TypeIgnoreGraduatedDate, e, nil
+	case model.KindStatus:
+		return normalize.TypeIgnoreStatus, e, nil
+	}
+	return "", e, fmt.Errorf("findings of kind '%s' cannot be excepted, please fix source data instead", f.Kind)
+}
+
+// selectFinding returns a finding to except and its exception: the one with a given fingerprint, or the one about
+// a given project and field that can be excepted; findings about the same field needing different exceptions are ambiguous
+func selectFinding(r *compare.Result, fingerprint, project, field string) (*model.Finding, string, normalize.Exception, error) {
+	if fingerprint != "" {
+		for i, f := range r.Findings {
+			if f.Fingerprint == fingerprint {
+				typ, e, err := findingException(r, f)
+				return &r.Findings[i], typ, e, err
+			}
+		}
+		return nil, "", normalize.Exception{}, fmt.Errorf("no finding with fingerprint '%s'", fingerprint)
+	}
+	project = strings.ToLower(strings.TrimSpace(project))
+	var (
+		found  *model.Finding
+		typ    string
+		e      normalize.Exception
+		errFix error
+	)
+	candidates := []string{}
+	for i, f := range r.Findings {
+		if f.Project != project || f.Field != field {
+			continue
+		}
+		t, exception, err := findingException(r, f)
+		if err != nil {
+			errFix = err
+			continue
+		}
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", f.Fingerprint, f.Kind))
+		if found == nil {
+			found, typ, e = &r.Findings[i], t, exception
+			continue
+		}
+		if t != typ || exception != e {
+			return nil, "", e, usageError{fmt.Errorf("findings about '%s' %s need different exceptions, use -fingerprint: %s", project, field, strings.Join(candidates, ", "))}
+		}
+	}
+	switch {
+	case found != nil:
+		return found, typ, e, nil
+	case errFix != nil:
+		return nil, "", e, errFix
+	}
+	return nil, "", e, fmt.Errorf("no finding about '%s' %s", project, field)
+}
+
+// askReason reads exception reason from a terminal
+func askReason(id string) (string, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return "", usageError{fmt.Errorf("-reason is required when not running in a terminal")}
+	}
+	fmt.Printf("reason for %s: ", id)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Printf("\n")
+		return "", usageError{fmt.Errorf("exception reason is required")}
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// exceptionsAdd implements exceptions add
+func exceptionsAdd(args []string) error {
+	o := &checkOptions{}
+	fs := newFlagSet("exceptions add", "")
+	o.registerInputs(fs)
+	fingerprint := fs.String("fingerprint", "", "fingerprint of a finding to except")
+	project := fs.String("project", "", "project of a finding to except (with -field), or of an exception of a given -type")
+	field := fs.String("field", "", "field of a finding to except: "+strings.Join(model.Fields, ", "))
+	typ := fs.String("type", "", "add an exception of a given type instead of excepting a finding: "+strings.Join(normalize.Types, ", "))
+	landscape := fs.String("expected-landscape", "", "with -type: landscape name (rename) or landscape repo (ignore-repo, default current)")
+	devstats := fs.String("expected-devstats", "", "with -type: DevStats repo (ignore-repo, default current)")
+	reason := fs.String("reason", "", "why the difference is accepted, asked for when not given")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *typ != "" && (*project == "" || *fingerprint != "" || *field != ""):
+		return usageError{fmt.Errorf("-type needs -project and cannot be used with -fingerprint or -field")}
+	case *typ == "" && *fingerprint == "" && (*project == "" || *field == ""):
+		return usageError{fmt.Errorf("-fingerprint, -project with -field, or -type with -project is required")}
+	case *typ == "" && *fingerprint != "" && (*project != "" || *field != ""):
+		return usageError{fmt.Errorf("-fingerprint cannot be used with -project or -field")}
+	}
+	store, err := loadStore(o, true)
+	if err != nil {
+		return err
+	}
+	c, err := newCheckerWith(o, store)
+	if err != nil {
+		return err
+	}
+	in, err := loader.Load(loader.ReadDefault, o.paths)
+	if err != nil {
+		return err
+	}
+	r := c.Compare(in)
+	var e normalize.Exception
+	if *typ != "" {
+		e = normalize.Exception{Project: strings.ToLower(strings.TrimSpace(*project)), Landscape: *landscape, DevStats: *devstats}
+		if *typ == normalize.TypeIgnoreRepo && e.Landscape == "" && e.DevStats == "" {
+			e.Landscape, e.DevStats = r.Repos(e.Project)
+		}
+	} else {
+		var found *model.Finding
+		found, *typ, e, err = selectFinding(r, *fingerprint, *project, *field)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("finding: %s (%s %s)\n", strings.TrimSuffix(found.Message, "\n"), found.Kind, found.Fingerprint)
+	}
+	id := normalize.ID(*typ, e.Project)
+	e.Reason = *reason
+	if strings.TrimSpace(e.Reason) == "" {
+		e.Reason, err = askReason(id)
+		if err != nil {
+			return err
+		}
+	}
+	replaced, err := store.Put(*typ, e)
+	if err != nil {
+		return usageError{err}
+	}
+	err = store.Save(o.exceptions)
+	if err != nil {
+		return err
+	}
+	action := "added"
+	if replaced {
+		action = "updated"
+	}
+	fmt.Printf("%s %s: %s\n", action, id, describe(*typ, e))
+	return nil
+}
+
+// exceptionsRemove implements exceptions remove
+func exceptionsRemove(args []string) error {
+	o := &checkOptions{}
+	fs := newFlagSet("exceptions remove", " [type/project]")
+	o.registerExceptions(fs)
+	project := fs.String("project", "", "remove exceptions of a given project applying to -field")
+	field := fs.String("field", "", "field whose exceptions are removed: "+strings.Join(model.Fields, ", "))
+	err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	ids := [][2]string{}
+	switch {
+	case fs.NArg() == 1 && *project == "" && *field == "":
+		typ, name, err := normalize.ParseID(fs.Arg(0))
+		if err != nil {
+			return usageError{err}
+		}
+		ids = append(ids, [2]string{typ, name})
+	case fs.NArg() == 0 && *project != "" && *field != "":
+		types, ok := fieldTypes[*field]
+		if !ok {
+			return usageError{fmt.Errorf("unknown field '%s', allowed: %s", *field, strings.Join(model.Fields, ", "))}
+		}
+		for _, typ := range types {
+			ids = append(ids, [2]string{typ, *project})
+		}
+	default:
+		fs.Usage()
+		return usageError{fmt.Errorf("exception ID (type/project) or -project with -field is required")}
+	}
+	store, err := loadStore(o, true)
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for _, id := range ids {
+		ok, err := store.Remove(id[0], id[1])
+		if err != nil {
+			return usageError{err}
+		}
+		if ok {
+			fmt.Printf("removed %s\n", normalize.ID(id[0], strings.ToLower(strings.TrimSpace(id[1]))))
+			removed++
+		}
+	}
+	if removed == 0 {
+		return fmt.Errorf("no matching exception found")
+	}
+	return store.Save(o.exceptions)
+}
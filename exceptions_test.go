@@ -0,0 +1,230 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// testStore is an exceptions store matching the compare package "exceptions" fixture
+const testStore = `rename:
+- project: renamed devstats
+  landscape: renamed landscape
+  reason: renamed
+skip:
+- project: all
+  reason: not a single project
+ignore_missing:
+- project: ignored missing
+  reason: listed twice
+ignore_repo:
+- project: outdated
+  landscape: outdated/landscape
+  devstats: outdated/devstats
+  reason: repo moved
+- project: pinned
+  landscape: pinned/landscape
+  devstats: pinned/devstats
+  reason: devstats is correct
+ignore_join_date:
+- project: ignoreddates
+  reason: join date differs
+- project: pinned
+  reason: no longer needed
+ignore_status:
+- project: ignoredstatus
+  reason: status differs
+`
+
+// testInputs returns flags setting inputs of the compare package "exceptions" fixture and a copy of the exceptions store
+func testInputs(t *testing.T) (string, []string) {
+	dir := filepath.Join("pkg", "compare", "testdata", "exceptions")
+	store := filepath.Join(t.TempDir(), "exceptions.yaml")
+	err := ioutil.WriteFile(store, []byte(testStore), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, []string{
+		"-exceptions", store,
+		"-landscape", filepath.Join(dir, "landscape.yml"),
+		"-projects", filepath.Join(dir, "projects.yaml"),
+		"-helm-projects", filepath.Join(dir, "helm-projects.yaml"),
+	}
+}
+
+func TestSuppressedBy(t *testing.T) {
+	_, args := testInputs(t)
+	o := &checkOptions{}
+	fs := newFlagSet("exceptions show", "")
+	o.registerInputs(fs)
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := loadStore(o, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newCheckerWith(o, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := loader.Load(loader.ReadDefault, o.paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		typ, project string
+		kinds        []string
+	}{
+		{typ: normalize.TypeIgnoreStatus, project: "ignoredstatus", kinds: []string{model.KindStatus}},
+		{typ: normalize.TypeIgnoreRepo, project: "pinned", kinds: []string{model.KindRepo}},
+		{typ: normalize.TypeIgnoreMissing, project: "ignored missing", kinds: []string{model.KindMissingDevstats}},
+		{typ: normalize.TypeIgnoreJoinDate, project: "ignoreddates", kinds: []string{model.KindJoin}},
+		// Stale exception: devstats and landscape join dates agree
+		{typ: normalize.TypeIgnoreJoinDate, project: "pinned", kinds: []string{}},
+		{typ: normalize.TypeSkip, project: "no such project", kinds: []string{}},
+	} {
+		before := len(store.Exceptions().IgnoreRepo)
+		found, err := suppressedBy(c, in, store, tc.typ, tc.project)
+		if err != nil {
+			t.Fatalf("%s/%s: %v", tc.typ, tc.project, err)
+		}
+		kinds := []string{}
+		for _, f := range found {
+			kinds = append(kinds, f.Kind)
+		}
+		if len(kinds) != len(tc.kinds) || (len(kinds) > 0 && kinds[0] != tc.kinds[0]) {
+			t.Errorf("%s/%s suppresses %v, want %v", tc.typ, tc.project, kinds, tc.kinds)
+		}
+		// The store is left as it was
+		e, _ := store.Get(tc.typ, tc.project)
+		if (e == nil) != (tc.typ == normalize.TypeSkip) || len(store.Exceptions().IgnoreRepo) != before {
+			t.Errorf("%s/%s: store changed", tc.typ, tc.project)
+		}
+	}
+}
+
+func TestSelectFinding(t *testing.T) {
+	r := &compare.Result{
+		Findings: []model.Finding{
+			model.NewFinding(model.KindInput, model.SeverityError, "", "", "error: reading"),
+			// Docker findings sort before missing ones, they cannot be excepted
+			model.NewFinding(model.KindDockerInDevstats, model.SeverityError, "alpha", "name", "error: alpha docker"),
+			model.NewFinding(model.KindMissingDevstats, model.SeverityError, "alpha", "name", "error: alpha missing"),
+			model.NewFinding(model.KindDockerInDevstats, model.SeverityError, "beta", "name", "error: beta docker"),
+			model.NewFinding(model.KindMissingDevstats, model.SeverityError, "gamma", "name", "error: gamma missing devstats"),
+			model.NewFinding(model.KindMissingLandscape, model.SeverityError, "gamma", "name", "error: gamma missing landscape"),
+		},
+		Aliases: map[string]string{},
+	}
+	missing := r.Findings[2].Fingerprint
+	for _, tc := range []struct {
+		name                        string
+		fingerprint, project, field string
+		want, typ, err              string
+	}{
+		{name: "fingerprint", fingerprint: missing, want: missing, typ: normalize.TypeIgnoreMissing},
+		{name: "fingerprint not exceptable", fingerprint: r.Findings[1].Fingerprint, err: "cannot be excepted"},
+		{name: "unknown fingerprint", fingerprint: "abc", err: "no finding with fingerprint 'abc'"},
+		{name: "exceptable one of field findings", project: " Alpha ", field: "name", want: missing, typ: normalize.TypeIgnoreMissing},
+		{name: "no exceptable field findings", project: "beta", field: "name", err: "cannot be excepted"},
+		{name: "ambiguous", project: "gamma", field: "name", err: "use -fingerprint"},
+		{name: "no field findings", project: "alpha", field: "repo", err: "no finding about 'alpha' repo"},
+	} {
+		f, typ, e, err := selectFinding(r, tc.fingerprint, tc.project, tc.field)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: got error %v, want '%s'", tc.name, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || f.Fingerprint != tc.want || typ != tc.typ || e.Project != f.Project {
+			t.Errorf("%s: got %+v, %s %+v, %v", tc.name, f, typ, e, err)
+		}
+	}
+}
+
+func TestExceptionsCommands(t *testing.T) {
+	store, inputs := testInputs(t)
+	args := func(a ...string) []string {
+		return append(append(a[:1:1], inputs...), a[1:]...)
+	}
+	for _, tc := range []struct {
+		name string
+		run  func([]string) error
+		args []string
+		err  bool
+		exit int
+	}{
+		{name: "no command", run: exceptions, args: []string{}, exit: 2},
+		{name: "unknown command", run: exceptions, args: []string{"purge"}, exit: 2},
+		{name: "list", run: exceptions, args: []string{"list", "-exceptions", store, "-type", normalize.TypeIgnoreRepo}},
+		{name: "list unknown flag", run: exceptions, args: []string{"list", "-exceptions", store, "-kind", "x"}, exit: 2},
+		{name: "show", run: exceptions, args: args("show", "ignore-status/ignoredstatus")},
+		{name: "show without ID", run: exceptions, args: args("show"), err: true},
+		{name: "show bad ID", run: exceptions, args: args("show", "ignoredstatus"), err: true},
+		{name: "show missing", run: exceptions, args: args("show", "skip/ignoredstatus"), err: true},
+		{name: "add without reason", run: exceptions, args: args("add", "-type", normalize.TypeSkip, "-project", "x"), err: true},
+		{name: "add type and field", run: exceptions, args: args("add", "-type", normalize.TypeSkip, "-project", "x", "-field", "name", "-reason", "r"), err: true},
+		{name: "add fingerprint and project", run: exceptions, args: args("add", "-fingerprint", "abc", "-project", "x", "-reason", "r"), err: true},
+		{name: "add unknown finding", run: exceptions, args: args("add", "-project", "outdated", "-field", "join", "-reason", "r"), err: true},
+		{name: "add finding", run: exceptions, args: args("add", "-project", "outdated", "-field", "repo", "-reason", "both repos moved")},
+		{name: "add type", run: exceptions, args: args("add", "-type", normalize.TypeSkip, "-project", "Legacy", "-reason", "not a CNCF project")},
+		{name: "add to url", run: exceptions, args: []string{"add", "-exceptions", "https://example.com/exceptions.yaml", "-type", normalize.TypeSkip, "-project", "x", "-reason", "r"}, err: true},
+		{name: "remove by ID", run: exceptions, args: []string{"remove", "-exceptions", store, "ignore-join-date/ignoreddates"}},
+		{name: "remove by field", run: exceptions, args: []string{"remove", "-exceptions", store, "-project", "ignoredstatus", "-field", "status"}},
+		{name: "remove unknown field", run: exceptions, args: []string{"remove", "-exceptions", store, "-project", "x", "-field", "color"}, err: true},
+		{name: "remove both", run: exceptions, args: []string{"remove", "-exceptions", store, "-project", "x", "skip/x"}, err: true},
+		{name: "remove missing", run: exceptions, args: []string{"remove", "-exceptions", store, "skip/x"}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.run(tc.args)
+			switch {
+			case tc.exit != 0:
+				if err != exitError(tc.exit) {
+					t.Errorf("got error %v, want exit code %d", err, tc.exit)
+				}
+			case tc.err && err == nil:
+				t.Errorf("succeeded, want an error")
+			case !tc.err && err != nil:
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+	data, err := ioutil.ReadFile(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := normalize.ParseStore(store, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]string{
+		"ignore-repo/outdated":          "both repos moved",
+		"skip/legacy":                   "not a CNCF project",
+		"ignore-join-date/ignoreddates": "",
+		"ignore-status/ignoredstatus":   "",
+		"ignore-repo/pinned":            "devstats is correct",
+	} {
+		typ, project, _ := normalize.ParseID(id)
+		e, _ := s.Get(typ, project)
+		got := ""
+		if e != nil {
+			got = e.Reason
+		}
+		if got != want {
+			t.Errorf("%s: got reason '%s', want '%s'", id, got, want)
+		}
+	}
+	e, _ := s.Get(normalize.TypeIgnoreRepo, "outdated")
+	if e == nil || e.Landscape != "outdated/landscape-new" || e.DevStats != "outdated/devstats-new" {
+		t.Errorf("ignore-repo/outdated not updated to current repos: %+v", e)
+	}
+}
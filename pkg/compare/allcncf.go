@@ -0,0 +1,71 @@
+package compare
+
+import (
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// How archived projects are checked in "All CNCF" repo groups: included like all other projects (default),
+// excluded (their repos must not be in any group) or not checked at all
+const (
+	AllArchivedInclude = "include"
+	AllArchivedExclude = "exclude"
+	AllArchivedIgnore  = "ignore"
+)
+
+// AllArchivedModes lists all valid Checker.AllArchived values, empty (or unknown) value is AllArchivedInclude
+var AllArchivedModes = []string{AllArchivedInclude, AllArchivedExclude, AllArchivedIgnore}
+
+// compareAllCNCF checks "All CNCF" repo groups (when configured) against devstats projects.yaml: main repo of every
+// enabled project must be assigned (by name or org) to a repo group named like the project, archived projects are
+// checked as set by AllArchived. The all project itself is usually skipped, so it is not compared anywhere else
+func (s *state) compareAllCNCF() {
+	if s.in.AllRepoGroups == nil {
+		return
+	}
+	// Last assignment wins, like when the SQL is executed
+	groupsByRepo := make(map[string]loader.RepoGroup)
+	groupsByOrg := make(map[string]loader.RepoGroup)
+	for _, group := range s.in.AllRepoGroups {
+		for _, repo := range group.Repos {
+			groupsByRepo[repo] = group
+		}
+		for _, org := range group.Orgs {
+			groupsByOrg[org] = group
+		}
+	}
+	errs := make(map[string]struct{})
+	for _, name := range sortedKeys(s.p.repos) {
+		repo := s.p.repos[name]
+		if repo == "" {
+			continue
+		}
+		archived := statusOf(s.p.byStatus, name) == "archived"
+		if archived && s.AllArchived == AllArchivedIgnore {
+			continue
+		}
+		group, ok := groupsByRepo[repo]
+		if !ok {
+			group, ok = groupsByOrg[strings.Split(repo, "/")[0]]
+		}
+		switch {
+		case archived && s.AllArchived == AllArchivedExclude:
+			if ok {
+				s.r.finding(model.KindAllCNCF, name, "status", "error: archived devstats project repo in All CNCF repo group '%s' '%s' <=> '%s'%s\n", name, repo, group.Name, loader.Locs(s.p.pos[name].Get("status"), group.Pos))
+				errs[name] = struct{}{}
+			}
+		case !ok:
+			s.r.finding(model.KindAllCNCF, name, "repo", "error: devstats main repo not in any All CNCF repo group '%s' '%s'%s\n", name, repo, loader.Locs(s.p.pos[name].Get("repo")))
+			errs[name] = struct{}{}
+		default:
+			resolved, _ := s.resolveAny(group.Name)
+			if resolved != name {
+				s.r.finding(model.KindAllCNCF, name, "name", "error: All CNCF repo group of devstats main repo not matching project '%s' '%s' <=> '%s'%s\n", name, repo, group.Name, loader.Locs(s.p.pos[name].Get("repo"), group.Pos))
+				errs[name] = struct{}{}
+			}
+		}
+	}
+	s.r.summary(model.KindAllCNCF, "error: All CNCF repo groups differences vs devstats projects.yaml: %d\n", len(errs))
+}
@@ -0,0 +1,722 @@
+// Package compare compares landscape.yml with DevStats projects.yaml files and reports findings.
+// Checker.Check loads inputs and compares them, Checker.Compare compares already loaded inputs.
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+	"github.com/cncf/devstatscode"
+)
+
+// Checker compares inputs using given exceptions, Policy sets severity of each check kind (nil: all errors),
+// Debug prints details of missing projects, AllArchived is how archived projects are checked in "All CNCF" (AllArchived*)
+type Checker struct {
+	Exceptions  *normalize.Exceptions
+	Policy      *model.Policy
+	Debug       bool
+	AllArchived string
+}
+
+// Result holds all output lines and findings of a single comparison
+// Report is set when anything was found, Aliases maps DevStats project names to landscape names and vice versa
+// StatusCounts holds number of projects per maturity level for model.SourceLandscape and model.SourceDevstats
+type Result struct {
+	Msgs         []string
+	Findings     []model.Finding
+	Report       bool
+	Aliases      map[string]string
+	Fetches      []model.FetchStat
+	StatusCounts map[string]map[string]int
+	policy       *model.Policy
+	reposL       map[string]string
+	reposP       map[string]string
+}
+
+// Repos returns normalized landscape and DevStats repos of a given landscape project name, as compared
+func (r *Result) Repos(project string) (landscape, devstats string) {
+	return r.reposL[project], r.reposP[project]
+}
+
+func newResult(policy *model.Policy) *Result {
+	return &Result{
+		policy:   policy,
+		Msgs:     []string{},
+		Findings: []model.Finding{},
+		Aliases:  make(map[string]string),
+		Fetches:  []model.FetchStat{},
+		StatusCounts: map[string]map[string]int{
+			model.SourceLandscape: make(map[string]int),
+			model.SourceDevstats:  make(map[string]int),
+		},
+	}
+}
+
+// printf adds an output line that is not a finding (for example a summary)
+func (r *Result) printf(format string, args ...interface{}) {
+	r.Msgs = append(r.Msgs, fmt.Sprintf(format, args...))
+}
+
+// withSeverity replaces "error:" prefix of a message with a given severity
+func withSeverity(severity, str string) string {
+	if strings.HasPrefix(str, "error:") {
+		return severity + strings.TrimPrefix(str, "error")
+	}
+	return str
+}
+
+// finding adds an output line reported as a finding of a given kind, unless the kind is turned off by policy
+func (r *Result) finding(kind, project, field, format string, args ...interface{}) {
+	severity := r.policy.Severity(kind)
+	if severity == model.SeverityOff {
+		return
+	}
+	str := withSeverity(severity, fmt.Sprintf(format, args...))
+	r.Msgs = append(r.Msgs, str)
+	r.Findings = append(r.Findings, model.NewFinding(kind, severity, project, field, strings.TrimSpace(str)))
+	r.Report = true
+}
+
+// summary adds a line with number of mismatches detected by a pass reporting a given kind, when there are any
+func (r *Result) summary(kind, format string, count int) {
+	severity := r.policy.Severity(kind)
+	if count == 0 || severity == model.SeverityOff {
+		return
+	}
+	r.Msgs = append(r.Msgs, withSeverity(severity, fmt.Sprintf(format, count)))
+}
+
+// Check reads inputs using a given reader and compares them, input errors are reported as findings and returned
+func (c *Checker) Check(read loader.Reader, paths loader.Paths) (*Result, error) {
+	in, err := loader.Load(read, paths)
+	if err != nil {
+		r := newResult(c.Policy)
+		r.Fetches = in.Fetches
+		r.finding(model.KindInput, "", "", "%v\n", err)
+		return r, err
+	}
+	r := c.Compare(in)
+	r.Fetches = in.Fetches
+	return r, nil
+}
+
+// Compare runs all comparison passes on loaded inputs
+func (c *Checker) Compare(in *loader.Inputs) *Result {
+	s := &state{
+		Checker:          c,
+		in:               in,
+		r:                newResult(c.Policy),
+		projectsNames:    make(map[string]struct{}),
+		landscapeNames:   make(map[string]struct{}),
+		disabledProjects: make(map[string]struct{}),
+		p:                newValues(),
+		d:                newValues(),
+		l:                newValues(),
+	}
+	s.collectDevstats()
+	s.collectDocker()
+	s.compareDockerInDevstats()
+	s.compareDevstatsInDocker()
+	s.collectLandscape()
+	s.compareMissingLandscape()
+	s.compareRepos()
+	s.compareDates(model.KindJoin, "join", "not equal to", s.l.joinDates, s.p.joinDates, s.Exceptions.IgnoreJoinDate, "error: %d join dates mismatches detected\n")
+	s.compareDates(model.KindIncubating, "incubating", "is not equal to", s.l.incubatingDates, s.p.incubatingDates, s.Exceptions.IgnoreIncubatingDate, "error: incubating dates mismatches detected: %d\n")
+	s.compareDates(model.KindGraduated, "graduated", "not equal to", s.l.graduatedDates, s.p.graduatedDates, s.Exceptions.IgnoreGraduatedDate, "error: graduated dates mismatches detected: %d\n")
+	s.compareStatuses()
+	s.compareCLOMonitor()
+	s.compareTOC()
+	s.compareMaintainers()
+	s.compareHelmValues()
+	s.compareWebsite()
+	s.compareAllCNCF()
+	model.SortFindings(s.r.Findings)
+	s.r.reposL, s.r.reposP = s.l.repos, s.p.repos
+	return s.r
+}
+
+// values holds normalized values of a single source keyed by landscape project name
+type values struct {
+	repos           map[string]string
+	joinDates       map[string]string
+	incubatingDates map[string]string
+	graduatedDates  map[string]string
+	byStatus        map[string]map[string]struct{}
+	pos             map[string]loader.FieldPos
+}
+
+func newValues() *values {
+	return &values{
+		repos:           make(map[string]string),
+		joinDates:       make(map[string]string),
+		incubatingDates: make(map[string]string),
+		graduatedDates:  make(map[string]string),
+		byStatus:        make(map[string]map[string]struct{}),
+		pos:             make(map[string]loader.FieldPos),
+	}
+}
+
+// addStatus records project's maturity level
+func (v *values) addStatus(status, name string) {
+	_, ok := v.byStatus[status]
+	if !ok {
+		v.byStatus[status] = make(map[string]struct{})
+	}
+	v.byStatus[status][name] = struct{}{}
+}
+
+// state holds values collected from all sources during a single comparison
+// p is devstats projects.yaml, d is devstats-docker-images projects.yaml and l is landscape.yml
+type state struct {
+	*Checker
+	in               *loader.Inputs
+	r                *Result
+	projectsNames    map[string]struct{}
+	landscapeNames   map[string]struct{}
+	disabledProjects map[string]struct{}
+	p                *values
+	d                *values
+	l                *values
+}
+
+// sortedKeys returns keys of a given map in alphabetical order, so all passes report in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedStatuses returns maturity levels of given projects by status in lifecycle order
+func sortedStatuses(byStatus map[string]map[string]struct{}) []string {
+	statuses := sortedKeys(byStatus)
+	model.SortStatuses(statuses)
+	return statuses
+}
+
+// byFullName returns keys of given DevStats projects ordered by their (mapped) full names
+func (s *state) byFullName(projects map[string]devstatscode.Project) []string {
+	keys := sortedKeys(projects)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return s.Exceptions.Name(projects[keys[i]].FullName) < s.Exceptions.Name(projects[keys[j]].FullName)
+	})
+	return keys
+}
+
+// skipped returns whether a given DevStats project (lower case key) should not be compared
+func (s *state) skipped(name string) bool {
+	_, skip := s.Exceptions.Skip[name]
+	return skip
+}
+
+// collectDevstats iterates devstats projects.yaml to get data
+func (s *state) collectDevstats() {
+	for _, key := range sortedKeys(s.in.Projects.Projects) {
+		data := s.in.Projects.Projects[key]
+		name := strings.ToLower(key)
+		if s.skipped(name) {
+			continue
+		}
+		if data.Disabled {
+			s.disabledProjects[name] = struct{}{}
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		s.projectsNames[fullName] = struct{}{}
+		s.p.pos[fullName] = s.in.PositionsP[name]
+		if name != fullName {
+			s.r.Aliases[name] = fullName
+			s.r.Aliases[fullName] = name
+		}
+		s.p.repos[fullName] = normalize.Repo(data.MainRepo)
+		s.p.joinDates[fullName] = normalize.DevstatsDate(data.JoinDate)
+		if data.IncubatingDate != nil {
+			s.p.incubatingDates[fullName] = normalize.DevstatsDate(data.IncubatingDate)
+		}
+		if data.GraduatedDate != nil {
+			s.p.graduatedDates[fullName] = normalize.DevstatsDate(data.GraduatedDate)
+		}
+		s.p.addStatus(normalize.Status(data.Status), fullName)
+	}
+}
+
+// collectDocker iterates devstats-docker-images projects.yaml to get data
+func (s *state) collectDocker() {
+	for _, key := range sortedKeys(s.in.Projects2.Projects) {
+		data := s.in.Projects2.Projects[key]
+		name := strings.ToLower(key)
+		if s.skipped(name) {
+			continue
+		}
+		if data.Disabled {
+			s.disabledProjects[name] = struct{}{}
+			continue
+		}
+		status := normalize.Status(data.Status)
+		if status == "-" || status == "" {
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		s.projectsNames[fullName] = struct{}{}
+		s.d.pos[fullName] = s.in.PositionsP2[name]
+		s.d.repos[fullName] = normalize.Repo(data.MainRepo)
+		s.d.joinDates[fullName] = normalize.DevstatsDate(data.JoinDate)
+		if data.IncubatingDate != nil {
+			s.d.incubatingDates[fullName] = normalize.DevstatsDate(data.IncubatingDate)
+		}
+		if data.GraduatedDate != nil {
+			s.d.graduatedDates[fullName] = normalize.DevstatsDate(data.GraduatedDate)
+		}
+		s.d.addStatus(status, fullName)
+	}
+}
+
+// compareDockerInDevstats iterates devstats-docker-images projects.yaml to check with devstats projects.yaml
+func (s *state) compareDockerInDevstats() {
+	diffFromDocker := 0
+	for _, key := range s.byFullName(s.in.Projects2.Projects) {
+		data := s.in.Projects2.Projects[key]
+		name := strings.ToLower(key)
+		if s.skipped(name) || data.Disabled {
+			continue
+		}
+		status := normalize.Status(data.Status)
+		if status == "-" || status == "" {
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		pos2 := s.in.PositionsP2[name]
+		posP := s.p.pos[fullName]
+		_, ok := s.projectsNames[fullName]
+		if !ok {
+			s.r.finding(model.KindDockerInDevstats, fullName, "name", "error: missing docker project in devstats projects: '%s'%s\n", fullName, loader.Locs(pos2.Get("name")))
+			diffFromDocker++
+		}
+		_, ok = s.p.byStatus[status][fullName]
+		if !ok {
+			s.r.finding(model.KindDockerInDevstats, fullName, "status", "error: missing or different status of docker project in devstats projects: %s '%s'%s\n", status, fullName, loader.Locs(pos2.Get("status"), posP.Get("status")))
+			diffFromDocker++
+		}
+		repoD := normalize.Repo(data.MainRepo)
+		repoP, ok := s.p.repos[fullName]
+		if !ok || repoP != repoD {
+			s.r.finding(model.KindDockerInDevstats, fullName, "repo", "error: missing or different docker main repo in devstats projects: %s '%s' <=> '%s'%s\n", fullName, repoD, repoP, loader.Locs(pos2.Get("repo"), posP.Get("repo")))
+			diffFromDocker++
+		}
+		joinDateD := normalize.DevstatsDate(data.JoinDate)
+		joinDateP, ok := s.p.joinDates[fullName]
+		if !ok || joinDateP != joinDateD {
+			s.r.finding(model.KindDockerInDevstats, fullName, "join", "error: missing or different docker join date in devstats projects: %s '%s' <=> '%s'%s\n", fullName, joinDateD, joinDateP, loader.Locs(pos2.Get("join"), posP.Get("join")))
+			diffFromDocker++
+		}
+		if data.IncubatingDate != nil {
+			incubatingDateD := normalize.DevstatsDate(data.IncubatingDate)
+			incubatingDateP, ok := s.p.incubatingDates[fullName]
+			if !ok || incubatingDateP != incubatingDateD {
+				s.r.finding(model.KindDockerInDevstats, fullName, "incubating", "error: missing or different docker incubating date in devstats projects: %s '%s' <=> '%s'%s\n", fullName, incubatingDateD, incubatingDateP, loader.Locs(pos2.Get("incubating"), posP.Get("incubating")))
+				diffFromDocker++
+			}
+		}
+		if data.GraduatedDate != nil {
+			graduatedDateD := normalize.DevstatsDate(data.GraduatedDate)
+			graduatedDateP, ok := s.p.graduatedDates[fullName]
+			if !ok || graduatedDateP != graduatedDateD {
+				s.r.finding(model.KindDockerInDevstats, fullName, "graduated", "error: missing or different docker graduated date in devstats projects: %s '%s' <=> '%s'%s\n", fullName, graduatedDateD, graduatedDateP, loader.Locs(pos2.Get("graduated"), posP.Get("graduated")))
+				diffFromDocker++
+			}
+		}
+	}
+	s.r.summary(model.KindDockerInDevstats, "error: devstats-docker-images projects.yaml differences vs devstats projects.yaml: %d\n", diffFromDocker)
+}
+
+// compareDevstatsInDocker iterates devstats projects.yaml to check with devstats-docker-images projects.yaml
+func (s *state) compareDevstatsInDocker() {
+	diffInDocker := 0
+	for _, key := range s.byFullName(s.in.Projects.Projects) {
+		data := s.in.Projects.Projects[key]
+		name := strings.ToLower(key)
+		if s.skipped(name) || data.Disabled {
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		pos := s.in.PositionsP[name]
+		posD := s.d.pos[fullName]
+		status := normalize.Status(data.Status)
+		_, ok := s.d.byStatus[status][fullName]
+		if !ok {
+			s.r.finding(model.KindDevstatsInDocker, fullName, "status", "error: missing or different status of devstats project in docker projects: %s '%s'%s\n", status, fullName, loader.Locs(pos.Get("status"), posD.Get("status")))
+			diffInDocker++
+		}
+		repoP := normalize.Repo(data.MainRepo)
+		repoD, ok := s.d.repos[fullName]
+		if !ok || repoD != repoP {
+			s.r.finding(model.KindDevstatsInDocker, fullName, "repo", "error: missing or different devstats main repo in docker projects: %s '%s' <=> '%s'%s\n", fullName, repoP, repoD, loader.Locs(pos.Get("repo"), posD.Get("repo")))
+			diffInDocker++
+		}
+		joinDateP := normalize.DevstatsDate(data.JoinDate)
+		joinDateD, ok := s.d.joinDates[fullName]
+		if !ok || joinDateD != joinDateP {
+			s.r.finding(model.KindDevstatsInDocker, fullName, "join", "error: missing or different devstats join date in docker projects: %s '%s' <=> '%s'%s\n", fullName, joinDateP, joinDateD, loader.Locs(pos.Get("join"), posD.Get("join")))
+			diffInDocker++
+		}
+		if data.IncubatingDate != nil {
+			incubatingDateP := normalize.DevstatsDate(data.IncubatingDate)
+			incubatingDateD, ok := s.d.incubatingDates[fullName]
+			if !ok || incubatingDateD != incubatingDateP {
+				s.r.finding(model.KindDevstatsInDocker, fullName, "incubating", "error: missing or different devstats incubating date in docker projects: %s '%s' <=> '%s'%s\n", fullName, incubatingDateP, incubatingDateD, loader.Locs(pos.Get("incubating"), posD.Get("incubating")))
+				diffInDocker++
+			}
+		}
+		if data.GraduatedDate != nil {
+			graduatedDateP := normalize.DevstatsDate(data.GraduatedDate)
+			graduatedDateD, ok := s.d.graduatedDates[fullName]
+			if !ok || graduatedDateD != graduatedDateP {
+				s.r.finding(model.KindDevstatsInDocker, fullName, "graduated", "error: missing or different devstats graduated date in docker projects: %s '%s' <=> '%s'%s\n", fullName, graduatedDateP, graduatedDateD, loader.Locs(pos.Get("graduated"), posD.Get("graduated")))
+				diffInDocker++
+			}
+		}
+	}
+	s.r.summary(model.KindDevstatsInDocker, "error: devstats projects.yaml differences vs devstats-docker-images projects.yaml: %d\n", diffInDocker)
+}
+
+// resolveName returns name of a DevStats project matching a given (lower case) name directly or via namesMapping (Aliases)
+func (s *state) resolveName(name string) (string, bool) {
+	_, ok := s.projectsNames[name]
+	if ok {
+		return name, true
+	}
+	mappedName, ok := s.r.Aliases[name]
+	if ok {
+		_, ok = s.projectsNames[mappedName]
+		if ok {
+			return mappedName, true
+		}
+	}
+	return "", false
+}
+
+// resolveAny returns name of a DevStats project matching any of given names (from other sources than landscape),
+// resolved like landscape items, also using renames
+func (s *state) resolveAny(names ...string) (string, bool) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		resolved, ok := s.resolveName(strings.ToLower(name))
+		if !ok {
+			resolved, ok = s.resolveName(s.Exceptions.Name(name))
+		}
+		if ok {
+			return resolved, true
+		}
+	}
+	return "", false
+}
+
+// collectLandscape iterates landscape items (from any landscape format) to compare with devstats, reports projects missing in DevStats
+// Only the first specified value of each field is used, no overwrite, especially with blank data
+func (s *state) collectLandscape() {
+	for _, item := range s.in.Landscape {
+		itemPos := item.Pos
+		name := strings.ToLower(item.Name)
+		resolved, ok := s.resolveName(name)
+		if ok {
+			name = resolved
+		}
+		status := normalize.Status(item.Project)
+		// Project can be missing in DevStats:projects.yaml
+		if !ok && (item.Accepted != "" || status != "") {
+			_, disabled := s.disabledProjects[name]
+			_, ignored := s.Exceptions.IgnoreMissing[name]
+			if !disabled && !ignored {
+				s.r.finding(model.KindMissingDevstats, name, "name", "error: missing in devstats projects: '%s'%s\n", name, loader.Locs(itemPos.Get("name")))
+				if s.Debug {
+					fmt.Printf("details: item: %+v, status: %+v, projectNames: %+v, namesMapping: %+v\n", item, status, s.projectsNames, s.r.Aliases)
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		var (
+			joinDt  string
+			incubDt string
+		)
+		s.landscapeNames[name] = struct{}{}
+		_, present := s.l.pos[name]
+		if !present {
+			s.l.pos[name] = loader.FieldPos{"name": itemPos.Get("name")}
+		}
+		_, present = s.l.repos[name]
+		if !present && item.RepoURL != "" {
+			s.l.repos[name] = normalize.RepoURL(item.RepoURL)
+			s.l.pos[name]["repo"] = itemPos.Get("repo")
+		}
+		_, present = s.l.joinDates[name]
+		if !present && item.Accepted != "" {
+			dtS := normalize.Date(item.Accepted)
+			s.l.joinDates[name] = dtS
+			s.l.pos[name]["join"] = itemPos.Get("join")
+			joinDt = dtS
+		}
+		_, present = s.l.incubatingDates[name]
+		if !present && item.Incubating != "" {
+			dtS := normalize.Date(item.Incubating)
+			if dtS > joinDt {
+				s.l.incubatingDates[name] = dtS
+				s.l.pos[name]["incubating"] = itemPos.Get("incubating")
+				incubDt = dtS
+			}
+		}
+		_, present = s.l.graduatedDates[name]
+		if !present && item.Graduated != "" {
+			dtS := normalize.Date(item.Graduated)
+			if (incubDt == "" && dtS > joinDt) || (incubDt != "" && dtS > incubDt && dtS > joinDt) {
+				s.l.graduatedDates[name] = dtS
+				s.l.pos[name]["graduated"] = itemPos.Get("graduated")
+			}
+		}
+		if status != "" {
+			_, present = s.l.pos[name]["status"]
+			if !present {
+				s.l.pos[name]["status"] = itemPos.Get("status")
+			}
+			s.l.addStatus(status, name)
+		}
+	}
+}
+
+// compareMissingLandscape reports DevStats projects missing in landscape
+func (s *state) compareMissingLandscape() {
+	for _, name := range sortedKeys(s.projectsNames) {
+		_, ok := s.landscapeNames[name]
+		if !ok {
+			pos, ok := s.p.pos[name]
+			if !ok {
+				pos = s.d.pos[name]
+			}
+			s.r.finding(model.KindMissingLandscape, name, "name", "error: missing in landscape: '%s'%s\n", name, loader.Locs(pos.Get("name")))
+		}
+	}
+}
+
+// compareRepos checks main repos/repo URLs
+func (s *state) compareRepos() {
+	reposErrs := make(map[string]struct{})
+	for _, project := range sortedKeys(s.l.repos) {
+		repoL := s.l.repos[project]
+		ignored, ignore := s.Exceptions.IgnoreRepo[project]
+		if ignore {
+			if ignored[0] == repoL {
+				continue
+			}
+			s.r.finding(model.KindRepoExceptionLandscape, project, "repo", "error: ignored landscape repo is incorrect '%s' '%s' <=> '%s'%s\n", project, repoL, ignored[0], loader.Locs(s.l.pos[project].Get("repo")))
+			reposErrs[project] = struct{}{}
+			continue
+		}
+		repoP, ok := s.p.repos[project]
+		if !ok {
+			s.r.finding(model.KindRepo, project, "repo", "error: landscape repo missing in devstats '%s' '%s'%s\n", project, repoL, loader.Locs(s.l.pos[project].Get("repo")))
+			reposErrs[project] = struct{}{}
+			continue
+		}
+		if repoL != repoP {
+			s.r.finding(model.KindRepo, project, "repo", "error: landscape repo not equal to devstats repo '%s' '%s' <=> '%s'%s\n", project, repoL, repoP, loader.Locs(s.l.pos[project].Get("repo"), s.p.pos[project].Get("repo")))
+			reposErrs[project] = struct{}{}
+		}
+	}
+	for _, project := range sortedKeys(s.p.repos) {
+		repoP := s.p.repos[project]
+		ignored, ignore := s.Exceptions.IgnoreRepo[project]
+		if ignore {
+			if ignored[1] == repoP {
+				continue
+			}
+			s.r.finding(model.KindRepoExceptionDevstats, project, "repo", "error: ignored devstats repo is incorrect '%s' '%s' <=> '%s'%s\n", project, repoP, ignored[1], loader.Locs(s.p.pos[project].Get("repo")))
+			reposErrs[project] = struct{}{}
+			continue
+		}
+		repoL, ok := s.l.repos[project]
+		if !ok {
+			s.r.finding(model.KindRepo, project, "repo", "error: devstats repo missing in landscape '%s' '%s'%s\n", project, repoP, loader.Locs(s.p.pos[project].Get("repo")))
+			reposErrs[project] = struct{}{}
+			continue
+		}
+		if repoL != repoP {
+			_, reported := reposErrs[project]
+			if !reported {
+				s.r.finding(model.KindRepo, project, "repo", "error: devstats repo not equal to landscape repo '%s' '%s' <=> '%s'%s\n", project, repoP, repoL, loader.Locs(s.p.pos[project].Get("repo"), s.l.pos[project].Get("repo")))
+				reposErrs[project] = struct{}{}
+			}
+		}
+	}
+	s.r.summary(model.KindRepo, "error: repos mismatches detected: %d\n", len(reposErrs))
+}
+
+// compareDates checks join/incubating/graduated dates (field), neq is how inequality is phrased in messages,
+// summary is printed with the number of mismatched projects
+func (s *state) compareDates(kind, field, neq string, datesL, datesP map[string]string, ignore map[string]struct{}, summary string) {
+	errs := make(map[string]struct{})
+	for _, project := range sortedKeys(datesL) {
+		dateL := datesL[project]
+		_, ignored := ignore[project]
+		if ignored {
+			continue
+		}
+		dateP, ok := datesP[project]
+		if !ok {
+			s.r.finding(kind, project, field, "error: landscape %s date missing in devstats '%s' '%s'%s\n", field, project, dateL, loader.Locs(s.l.pos[project].Get(field)))
+			errs[project] = struct{}{}
+			continue
+		}
+		if dateL != dateP {
+			s.r.finding(kind, project, field, "error: landscape %s date %s devstats %s date '%s' '%s' <=> '%s'%s\n", field, neq, field, project, dateL, dateP, loader.Locs(s.l.pos[project].Get(field), s.p.pos[project].Get(field)))
+			errs[project] = struct{}{}
+		}
+	}
+	for _, project := range sortedKeys(datesP) {
+		dateP := datesP[project]
+		_, ignored := ignore[project]
+		if ignored {
+			continue
+		}
+		dateL, ok := datesL[project]
+		if !ok {
+			s.r.finding(kind, project, field, "error: devstats %s date missing in landscape '%s' '%s'%s\n", field, project, dateP, loader.Locs(s.p.pos[project].Get(field)))
+			errs[project] = struct{}{}
+			continue
+		}
+		if dateL != dateP {
+			_, reported := errs[project]
+			if !reported {
+				s.r.finding(kind, project, field, "error: devstats %s date %s landscape %s date '%s' '%s' <=> '%s'%s\n", field, neq, field, project, dateP, dateL, loader.Locs(s.p.pos[project].Get(field), s.l.pos[project].Get(field)))
+				errs[project] = struct{}{}
+			}
+		}
+	}
+	s.r.summary(kind, summary, len(errs))
+}
+
+// otherStatus returns ", but is present in <status>" when a project has another status in given projects by status
+func otherStatus(byStatus map[string]map[string]struct{}, project string) string {
+	status := statusOf(byStatus, project)
+	if status != "" {
+		return fmt.Sprintf(", but is present in %s", status)
+	}
+	return ""
+}
+
+// compareStatuses checks maturity levels/statuses and number of projects in each of them
+func (s *state) compareStatuses() {
+	statusCountsL := s.r.StatusCounts[model.SourceLandscape]
+	statusCountsP := s.r.StatusCounts[model.SourceDevstats]
+	statusErrs := make(map[string]struct{})
+	for _, status := range sortedStatuses(s.l.byStatus) {
+		for _, project := range sortedKeys(s.l.byStatus[status]) {
+			_, ignore := s.Exceptions.IgnoreStatus[project]
+			if ignore {
+				continue
+			}
+			_, ok := s.p.byStatus[status][project]
+			if !ok {
+				s.r.finding(model.KindStatus, project, "status", "error: devstats is missing %s '%s'%s%s\n", status, project, otherStatus(s.p.byStatus, project), loader.Locs(s.l.pos[project].Get("status"), s.p.pos[project].Get("status")))
+				statusErrs[project] = struct{}{}
+				continue
+			}
+			statusCountsL[status]++
+		}
+	}
+	for _, status := range sortedStatuses(s.p.byStatus) {
+		for _, project := range sortedKeys(s.p.byStatus[status]) {
+			_, ignore := s.Exceptions.IgnoreStatus[project]
+			if ignore {
+				continue
+			}
+			_, ok := s.l.byStatus[status][project]
+			if !ok {
+				_, reported := statusErrs[project]
+				if !reported {
+					s.r.finding(model.KindStatus, project, "status", "error: landscape is missing %s '%s'%s%s\n", status, project, otherStatus(s.l.byStatus, project), loader.Locs(s.p.pos[project].Get("status"), s.l.pos[project].Get("status")))
+					statusErrs[project] = struct{}{}
+				}
+			}
+			statusCountsP[status]++
+		}
+	}
+	s.r.summary(model.KindStatus, "error: status mismatches detected: %d\n", len(statusErrs))
+	statuses := sortedKeys(statusCountsL)
+	model.SortStatuses(statuses)
+	for _, status := range statuses {
+		countL := statusCountsL[status]
+		countP, ok := statusCountsP[status]
+		if ok && countP == countL {
+			s.r.printf("%s: %d projects\n", status, countL)
+			continue
+		}
+		s.r.finding(model.KindStatusCount, "", status, "error: %s: %d landscape projects, %d devstats projects\n", status, countL, countP)
+	}
+}
+
+// statusOf returns maturity level of a project in given projects by status, empty when it has none
+func statusOf(byStatus map[string]map[string]struct{}, project string) string {
+	for _, status := range sortedStatuses(byStatus) {
+		_, ok := byStatus[status][project]
+		if ok {
+			return status
+		}
+	}
+	return ""
+}
+
+// compareCLOMonitor checks maturity levels, accepted dates and repos of CLOMonitor projects (when configured) against devstats
+// projects.yaml, CLOMonitor projects are resolved by display name or ID the same way as landscape items, also using renames
+// CLOMonitor projects not found in devstats are reported too, unless they are disabled there
+func (s *state) compareCLOMonitor() {
+	if s.in.CLOMonitor == nil {
+		return
+	}
+	errs := make(map[string]struct{})
+	for _, project := range s.in.CLOMonitor {
+		name, ok := s.resolveAny(project.DisplayName, project.Name)
+		if !ok {
+			_, disabled := s.disabledProjects[strings.ToLower(project.Name)]
+			if !disabled {
+				name = s.Exceptions.Name(project.Name)
+				s.r.finding(model.KindCLOMonitor, name, "name", "error: clomonitor %s project missing in devstats projects: '%s'%s\n", normalize.Status(project.Maturity), name, loader.Locs(project.Pos.Get("name")))
+				errs[name] = struct{}{}
+			}
+			continue
+		}
+		statusC, statusP := normalize.Status(project.Maturity), statusOf(s.p.byStatus, name)
+		if statusC != "" && statusC != statusP {
+			s.r.finding(model.KindCLOMonitor, name, "status", "error: clomonitor maturity not equal to devstats status '%s' '%s' <=> '%s'%s\n", name, statusC, statusP, loader.Locs(project.Pos.Get("status"), s.p.pos[name].Get("status")))
+			errs[name] = struct{}{}
+		}
+		dateC, dateP := normalize.Date(project.AcceptedAt), s.p.joinDates[name]
+		if dateC != "" && dateC != dateP {
+			s.r.finding(model.KindCLOMonitor, name, "join", "error: clomonitor accepted date not equal to devstats join date '%s' '%s' <=> '%s'%s\n", name, dateC, dateP, loader.Locs(project.Pos.Get("join"), s.p.pos[name].Get("join")))
+			errs[name] = struct{}{}
+		}
+		repoP := s.p.repos[name]
+		if len(project.Repos) > 0 {
+			found := false
+			for _, repo := range project.Repos {
+				if normalize.RepoURL(repo) == repoP {
+					found = true
+					break
+				}
+			}
+			if !found {
+				s.r.finding(model.KindCLOMonitor, name, "repo", "error: devstats repo missing in clomonitor repos '%s' '%s'%s\n", name, repoP, loader.Locs(s.p.pos[name].Get("repo"), project.Pos.Get("repo")))
+				errs[name] = struct{}{}
+			}
+		}
+	}
+	s.r.summary(model.KindCLOMonitor, "error: clomonitor differences vs devstats projects.yaml: %d\n", len(errs))
+}
@@ -0,0 +1,287 @@
+package compare_test
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var update = flag.Bool("update", false, "regenerate expected findings files in testdata/")
+
+// testExceptions returns exceptions used by fixtures, every exception kind has at least one entry
+func testExceptions() *normalize.Exceptions {
+	return &normalize.Exceptions{
+		DevstatsToLandscape: map[string]string{"renamed devstats": "renamed landscape"},
+		Skip:                map[string]struct{}{"all": {}},
+		IgnoreMissing:       map[string]struct{}{"ignored missing": {}},
+		IgnoreRepo: map[string][2]string{
+			"pinned":   {"pinned/landscape", "pinned/devstats"},
+			"outdated": {"outdated/landscape", "outdated/devstats"},
+		},
+		IgnoreJoinDate:       map[string]struct{}{"ignoreddates": {}},
+		IgnoreIncubatingDate: map[string]struct{}{"ignoreddates": {}},
+		IgnoreGraduatedDate:  map[string]struct{}{"ignoreddates": {}},
+		IgnoreStatus:         map[string]struct{}{"ignoredstatus": {}},
+	}
+}
+
+// testPolicy returns severity policy of a testdata case (severity.yaml), all kinds are errors when there is none
+func testPolicy(dir string) (*model.Policy, error) {
+	policy := model.DefaultPolicy()
+	data, err := ioutil.ReadFile(filepath.Join(dir, "severity.yaml"))
+	if err != nil {
+		return policy, nil
+	}
+	err = yaml.Unmarshal(data, policy)
+	if err != nil {
+		return nil, err
+	}
+	return policy, policy.Validate()
+}
+
+// baseDir holds shared base inputs, cases without their own projects.yaml are overlays on top of it:
+// they only add optional sources or replace some of the base inputs
+var baseDir = filepath.Join("testdata", "in-sync")
+
+// optionalSources maps optional source files of a case to their input locations
+var optionalSources = []struct {
+	file string
+	path func(*loader.Paths) *string
+}{
+	{"clomonitor.yaml", func(p *loader.Paths) *string { return &p.CLOMonitor }},
+	{"toc.md", func(p *loader.Paths) *string { return &p.TOC }},
+	{"maintainers.csv", func(p *loader.Paths) *string { return &p.Maintainers }},
+	{"helm-values.yaml", func(p *loader.Paths) *string { return &p.HelmValues }},
+	{"index.html", func(p *loader.Paths) *string { return &p.Website }},
+	{"repo_groups.sql", func(p *loader.Paths) *string { return &p.AllRepoGroups }},
+}
+
+// exists returns whether a given file exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// input returns a base input of a case, overlays fall back to baseDir for inputs they do not replace
+func input(dir string, names ...string) string {
+	overlay := !exists(filepath.Join(dir, "projects.yaml"))
+	for _, d := range []string{dir, baseDir} {
+		for _, name := range names {
+			if exists(filepath.Join(d, name)) {
+				return filepath.Join(d, name)
+			}
+		}
+		if !overlay {
+			break
+		}
+	}
+	// Missing inputs are reported as input findings
+	return filepath.Join(dir, names[len(names)-1])
+}
+
+// testPaths returns input locations of a case: landscape (landscape2 data.yml, full.json or landscape.yml), projects.yaml,
+// helm-projects.yaml and all optional sources present in the case directory
+func testPaths(dir string) loader.Paths {
+	paths := loader.Paths{
+		Landscape: input(dir, "data.yml", "full.json", "landscape.yml"),
+		Projects:  input(dir, "projects.yaml"),
+		Projects2: input(dir, "helm-projects.yaml"),
+	}
+	for _, source := range optionalSources {
+		if exists(filepath.Join(dir, source.file)) {
+			*source.path(&paths) = filepath.Join(dir, source.file)
+		}
+	}
+	return paths
+}
+
+// check runs the checker on a single testdata case directory
+func check(t *testing.T, dir string) *compare.Result {
+	policy, err := testPolicy(dir)
+	if err != nil {
+		t.Fatalf("%s: %v", dir, err)
+	}
+	return checkWith(dir, &compare.Checker{Exceptions: testExceptions(), Policy: policy})
+}
+
+// checkWith runs a given checker on a single testdata case directory
+func checkWith(dir string, c *compare.Checker) *compare.Result {
+	// Input errors are reported as findings too
+	r, _ := c.Check(loader.ReadDefault, testPaths(dir))
+	return r
+}
+
+// render returns findings (kind, severity, project, field and message) and remaining output lines, both in reported order
+func render(r *compare.Result) string {
+	findings := []string{}
+	reported := make(map[string]struct{})
+	for _, f := range r.Findings {
+		findings = append(findings, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", f.Kind, f.Severity, f.Project, f.Field, f.Message))
+		reported[f.Message] = struct{}{}
+	}
+	other := []string{}
+	for _, msg := range r.Msgs {
+		msg = strings.TrimSpace(msg)
+		_, ok := reported[msg]
+		if !ok {
+			other = append(other, msg)
+		}
+	}
+	return "# findings\n" + strings.Join(append(findings, "# output"), "\n") + "\n" + strings.Join(other, "\n") + "\n"
+}
+
+func cases(t *testing.T) []string {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("no test cases found in testdata/")
+	}
+	return dirs
+}
+
+func TestGolden(t *testing.T) {
+	for _, dir := range cases(t) {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			got := render(check(t, dir))
+			golden := filepath.Join(dir, "expected.golden")
+			if *update {
+				err := ioutil.WriteFile(golden, []byte(got), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run 'go test ./pkg/compare -update' to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("findings differ from %s (run 'go test ./pkg/compare -update' to regenerate)\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// TestGoldenCoverage makes sure fixtures keep covering every finding kind and the status summary
+func TestGoldenCoverage(t *testing.T) {
+	kinds := make(map[string]struct{})
+	summary := false
+	for _, dir := range cases(t) {
+		r := check(t, dir)
+		for _, f := range r.Findings {
+			kinds[f.Kind] = struct{}{}
+		}
+		for _, msg := range r.Msgs {
+			if strings.HasSuffix(msg, " projects\n") && !strings.HasPrefix(msg, "error:") {
+				summary = true
+			}
+		}
+	}
+	for kind := range model.KindCategories {
+		_, ok := kinds[kind]
+		if !ok {
+			t.Errorf("no test case reports findings of kind '%s'", kind)
+		}
+	}
+	if !summary {
+		t.Errorf("no test case reports status counts summary")
+	}
+}
+
+// TestTOCIgnoreStatus makes sure TOC maturity is not compared for projects whose status is ignored, while membership still is
+func TestTOCIgnoreStatus(t *testing.T) {
+	dir := filepath.Join("testdata", "toc")
+	e := testExceptions()
+	e.IgnoreStatus["alpha"] = struct{}{}
+	e.IgnoreStatus["zeta"] = struct{}{}
+	r := checkWith(dir, &compare.Checker{Exceptions: e, Policy: model.DefaultPolicy()})
+	fields := make(map[string]bool)
+	for _, f := range r.Findings {
+		if f.Kind == model.KindTOCDevstats || f.Kind == model.KindTOCLandscape {
+			fields[f.Project+"/"+f.Field] = true
+		}
+	}
+	if fields["alpha/status"] {
+		t.Errorf("toc status reported for a project with ignored status: %v", r.Findings)
+	}
+	if !fields["zeta/name"] {
+		t.Errorf("toc membership not reported for a project with ignored status: %v", r.Findings)
+	}
+}
+
+// TestAllArchivedModes checks how an archived (but enabled) DevStats project is checked in "All CNCF" repo groups
+func TestAllArchivedModes(t *testing.T) {
+	dir := filepath.Join("testdata", "all-cncf")
+	data, err := ioutil.ReadFile(filepath.Join(baseDir, "projects.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// alpha is the first sandbox project, its repo is in the Alpha group
+	projects := filepath.Join(t.TempDir(), "projects.yaml")
+	err = ioutil.WriteFile(projects, []byte(strings.Replace(string(data), "status: Sandbox", "status: Archived", 1)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		mode  string
+		field string
+	}{
+		{mode: "", field: ""},
+		{mode: compare.AllArchivedInclude, field: ""},
+		{mode: compare.AllArchivedExclude, field: "status"},
+		{mode: compare.AllArchivedIgnore, field: ""},
+	} {
+		t.Run("mode "+tc.mode, func(t *testing.T) {
+			paths := testPaths(dir)
+			paths.Projects = projects
+			c := &compare.Checker{Exceptions: testExceptions(), Policy: model.DefaultPolicy(), AllArchived: tc.mode}
+			r, _ := c.Check(loader.ReadDefault, paths)
+			field := ""
+			for _, f := range r.Findings {
+				if f.Kind == model.KindAllCNCF && f.Project == "alpha" {
+					field = f.Field
+				}
+			}
+			if field != tc.field {
+				t.Errorf("got alpha finding field '%s', want '%s'", field, tc.field)
+			}
+		})
+	}
+	// Ignored archived projects are not checked even when their repo is not in any group
+	data, err = ioutil.ReadFile(filepath.Join(dir, "repo_groups.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := filepath.Join(t.TempDir(), "repo_groups.sql")
+	err = ioutil.WriteFile(groups, []byte(strings.Replace(string(data), "'alpha/alpha',", "", 1)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mode, field := range map[string]string{compare.AllArchivedInclude: "repo", compare.AllArchivedExclude: "", compare.AllArchivedIgnore: ""} {
+		paths := testPaths(dir)
+		paths.Projects, paths.AllRepoGroups = projects, groups
+		r, _ := (&compare.Checker{Exceptions: testExceptions(), Policy: model.DefaultPolicy(), AllArchived: mode}).Check(loader.ReadDefault, paths)
+		got := ""
+		for _, f := range r.Findings {
+			if f.Kind == model.KindAllCNCF && f.Project == "alpha" {
+				got = f.Field
+			}
+		}
+		if got != field {
+			t.Errorf("mode %s without alpha repo: got field '%s', want '%s'", mode, got, field)
+		}
+	}
+}
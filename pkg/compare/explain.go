@@ -0,0 +1,342 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+	"github.com/cncf/devstatscode"
+)
+
+// resolve returns DevStats keys (lower case) matching a query (DevStats key, full name or landscape name)
+// in given projects and the landscape name they map to
+func (c *Checker) resolve(projects map[string]devstatscode.Project, query string) (keys []string, name string) {
+	for _, key := range sortedKeys(projects) {
+		data := projects[key]
+		fullName := c.Exceptions.Name(data.FullName)
+		if strings.ToLower(key) == query || strings.ToLower(data.FullName) == query || fullName == query {
+			keys = append(keys, key)
+			name = fullName
+		}
+	}
+	return
+}
+
+// dateOrDash returns a DevStats date or "-" when it is not set
+func dateOrDash(dt string) string {
+	if dt == "" {
+		return "-"
+	}
+	return dt
+}
+
+// explainProject prints a single DevStats project record
+func explainProject(w io.Writer, title string, key string, data devstatscode.Project, pos loader.FieldPos) {
+	fmt.Fprintf(w, "%s: '%s'%s\n", title, key, loader.Locs(pos.Get("name")))
+	fmt.Fprintf(w, "  name: %s\n", data.FullName)
+	fmt.Fprintf(w, "  status: %s%s\n", data.Status, loader.Locs(pos.Get("status")))
+	fmt.Fprintf(w, "  main_repo: %s%s\n", data.MainRepo, loader.Locs(pos.Get("repo")))
+	fmt.Fprintf(w, "  join_date: %s%s\n", dateOrDash(normalize.DevstatsDate(data.JoinDate)), loader.Locs(pos.Get("join")))
+	fmt.Fprintf(w, "  incubating_date: %s%s\n", dateOrDash(normalize.DevstatsDate(data.IncubatingDate)), loader.Locs(pos.Get("incubating")))
+	fmt.Fprintf(w, "  graduated_date: %s%s\n", dateOrDash(normalize.DevstatsDate(data.GraduatedDate)), loader.Locs(pos.Get("graduated")))
+	fmt.Fprintf(w, "  disabled: %v\n", data.Disabled)
+}
+
+// matches returns whether any of given names from other sources than landscape resolves to a given landscape name
+func matches(e *normalize.Exceptions, aliases map[string]string, name string, candidates ...string) bool {
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if candidate != "" && (lower == name || aliases[lower] == name || e.Name(candidate) == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// explainTOC prints CNCF TOC projects list entries resolving to a given landscape name
+func explainTOC(w io.Writer, e *normalize.Exceptions, projects []loader.TOCProject, aliases map[string]string, name string) {
+	found := false
+	for _, project := range projects {
+		if !matches(e, aliases, name, project.Name) {
+			continue
+		}
+		found = true
+		fmt.Fprintf(w, "%s: '%s' %s%s\n", loader.TOCFile, project.Name, project.Maturity, loader.Locs(project.Pos.Get("name")))
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.TOCFile)
+	}
+}
+
+// explainMaintainers prints CNCF project maintainers list entries resolving to a given landscape name
+func explainMaintainers(w io.Writer, e *normalize.Exceptions, projects []loader.MaintainersProject, aliases map[string]string, name string) {
+	found := false
+	for _, project := range projects {
+		if !matches(e, aliases, name, project.Name) {
+			continue
+		}
+		found = true
+		fmt.Fprintf(w, "%s: '%s' %s%s\n", loader.MaintainersFile, project.Name, project.Maturity, loader.Locs(project.Pos.Get("name")))
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.MaintainersFile)
+	}
+}
+
+// explainHelmValues prints devstats-helm values.yaml indexes of given devstats-helm keys and ranges they are in
+func explainHelmValues(w io.Writer, values *loader.HelmValues, keys []string) {
+	found := false
+	for i, proj := range values.Projects {
+		for _, key := range keys {
+			if strings.ToLower(proj) != strings.ToLower(key) {
+				continue
+			}
+			found = true
+			ranges := []string{}
+			for _, r := range values.Ranges {
+				in := "outside"
+				if r.Contains(i) {
+					in = "inside"
+				}
+				ranges = append(ranges, fmt.Sprintf("%s [%d, %d) %s", r.Name, r.From, r.To, in))
+			}
+			fmt.Fprintf(w, "%s: '%s' index %d%s\n", loader.HelmValuesFile, proj, i, loader.Locs(values.Pos[i].Get("name")))
+			fmt.Fprintf(w, "  ranges: %s\n", strings.Join(ranges, ", "))
+		}
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.HelmValuesFile)
+	}
+}
+
+// explainWebsite prints DevStats index page entries resolving to a given landscape name or linking to subdomains of given keys
+func explainWebsite(w io.Writer, e *normalize.Exceptions, projects []loader.WebsiteProject, aliases map[string]string, name string, keys []string) {
+	found := false
+	for _, project := range projects {
+		ok := matches(e, aliases, name, project.Name)
+		for _, key := range keys {
+			if normalize.Subdomain(key) == project.Subdomain {
+				ok = true
+			}
+		}
+		if !ok {
+			continue
+		}
+		found = true
+		fmt.Fprintf(w, "%s: '%s' %s %s%s\n", loader.WebsiteFile, project.Name, project.Maturity, project.Subdomain, loader.Locs(project.Pos.Get("name")))
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.WebsiteFile)
+	}
+}
+
+// explainAllCNCF prints "All CNCF" repo groups having main repos of given DevStats projects (by repo name or org)
+func explainAllCNCF(w io.Writer, groups []loader.RepoGroup, repos []string) {
+	found := false
+	for _, group := range groups {
+		for _, repo := range repos {
+			repo = strings.ToLower(repo)
+			org := strings.Split(repo, "/")[0]
+			for _, assigned := range append(append([]string{}, group.Repos...), group.Orgs...) {
+				if assigned != repo && assigned != org {
+					continue
+				}
+				found = true
+				fmt.Fprintf(w, "%s: '%s' %s%s\n", loader.AllRepoGroupsFile, group.Name, assigned, loader.Locs(group.Pos))
+			}
+		}
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.AllRepoGroupsFile)
+	}
+}
+
+// explainCLOMonitor prints CLOMonitor projects whose display name or ID resolves to a given landscape name
+func explainCLOMonitor(w io.Writer, e *normalize.Exceptions, projects []loader.CLOMonitorProject, aliases map[string]string, name string) {
+	found := false
+	for _, project := range projects {
+		if !matches(e, aliases, name, project.DisplayName, project.Name) {
+			continue
+		}
+		found = true
+		fmt.Fprintf(w, "%s: '%s'%s\n", loader.CLOMonitorFile, project.Name, loader.Locs(project.Pos.Get("name")))
+		if project.DisplayName != "" {
+			fmt.Fprintf(w, "  display_name: %s\n", project.DisplayName)
+		}
+		fmt.Fprintf(w, "  maturity: %s%s\n", project.Maturity, loader.Locs(project.Pos.Get("status")))
+		fmt.Fprintf(w, "  accepted_at: %s%s\n", dateOrDash(project.AcceptedAt), loader.Locs(project.Pos.Get("join")))
+		fmt.Fprintf(w, "  repositories: %s%s\n", strings.Join(project.Repos, ", "), loader.Locs(project.Pos.Get("repo")))
+		if project.DevStatsURL != "" {
+			fmt.Fprintf(w, "  devstats_url: %s\n", project.DevStatsURL)
+		}
+	}
+	if !found {
+		fmt.Fprintf(w, "%s: missing\n", loader.CLOMonitorFile)
+	}
+}
+
+// exceptionsOf returns descriptions of all exceptions applying to a given DevStats key and landscape name
+func (c *Checker) exceptionsOf(keys []string, name string) []string {
+	e := c.Exceptions
+	applied := []string{}
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		key = strings.ToLower(key)
+		_, dup := seen[key]
+		seen[key] = struct{}{}
+		_, ok := e.Skip[key]
+		if ok && !dup {
+			applied = append(applied, fmt.Sprintf("skip: DevStats project '%s' is not compared at all", key))
+		}
+	}
+	for _, devstats := range sortedKeys(e.DevstatsToLandscape) {
+		landscape := e.DevstatsToLandscape[devstats]
+		if strings.ToLower(landscape) == name {
+			applied = append(applied, fmt.Sprintf("rename: DevStats '%s' is '%s' in landscape", devstats, landscape))
+		}
+	}
+	_, ok := e.IgnoreMissing[name]
+	if ok {
+		applied = append(applied, "ignore missing: landscape entry can be missing in DevStats")
+	}
+	repos, ok := e.IgnoreRepo[name]
+	if ok {
+		applied = append(applied, fmt.Sprintf("ignore repo: landscape '%s', DevStats '%s' are expected", repos[0], repos[1]))
+	}
+	ignores := []struct {
+		what   string
+		ignore map[string]struct{}
+	}{
+		{"join date", e.IgnoreJoinDate},
+		{"incubating date", e.IgnoreIncubatingDate},
+		{"graduated date", e.IgnoreGraduatedDate},
+		{"status", e.IgnoreStatus},
+	}
+	for _, i := range ignores {
+		_, ok := i.ignore[name]
+		if ok {
+			applied = append(applied, fmt.Sprintf("ignore %s: not compared", i.what))
+		}
+	}
+	return applied
+}
+
+// Explain prints everything known about a project given by DevStats key, DevStats full name or landscape name:
+// resolved identity, each source's record, all landscape occurrences, applying exceptions and resulting findings
+func (c *Checker) Explain(w io.Writer, in *loader.Inputs, query string) error {
+	query = strings.ToLower(strings.TrimSpace(query))
+	keys, name := c.resolve(in.Projects.Projects, query)
+	keys2, name2 := c.resolve(in.Projects2.Projects, query)
+	if name == "" {
+		name = name2
+	}
+	if name == "" {
+		name = c.Exceptions.Name(query)
+	}
+	r := c.Compare(in)
+	// Landscape items can use a DevStats key as their name, namesMapping (Aliases) maps it to the full name
+	occurrences := []string{}
+	for _, item := range in.Landscape {
+		itemName := strings.ToLower(item.Name)
+		if itemName != name && r.Aliases[itemName] != name {
+			continue
+		}
+		pos := item.Pos
+		occurrence := fmt.Sprintf("%s / %s: '%s'%s\n", item.Category, item.Subcategory, item.Name, loader.Locs(pos.Get("name")))
+		occurrence += fmt.Sprintf("  project: %s%s\n", item.Project, loader.Locs(pos.Get("status")))
+		occurrence += fmt.Sprintf("  repo_url: %s%s\n", item.RepoURL, loader.Locs(pos.Get("repo")))
+		if len(item.AdditionalRepos) > 0 {
+			occurrence += fmt.Sprintf("  additional_repos: %s\n", strings.Join(item.AdditionalRepos, ", "))
+		}
+		occurrence += fmt.Sprintf("  accepted: %s%s\n", dateOrDash(item.Accepted), loader.Locs(pos.Get("join")))
+		occurrence += fmt.Sprintf("  incubating: %s%s\n", dateOrDash(item.Incubating), loader.Locs(pos.Get("incubating")))
+		occurrence += fmt.Sprintf("  graduated: %s%s\n", dateOrDash(item.Graduated), loader.Locs(pos.Get("graduated")))
+		if item.Archived != "" {
+			occurrence += fmt.Sprintf("  archived: %s%s\n", item.Archived, loader.Locs(pos.Get("archived")))
+		}
+		if item.DevStatsURL != "" {
+			occurrence += fmt.Sprintf("  devstats_url: %s\n", item.DevStatsURL)
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	if len(keys) == 0 && len(keys2) == 0 && len(occurrences) == 0 {
+		return fmt.Errorf("project '%s' not found in any source", query)
+	}
+	fmt.Fprintf(w, "project: '%s'\n", name)
+	if len(keys) > 0 {
+		fmt.Fprintf(w, "  devstats keys: %s\n", strings.Join(keys, ", "))
+	}
+	if len(keys2) > 0 && strings.Join(keys2, ",") != strings.Join(keys, ",") {
+		fmt.Fprintf(w, "  devstats-helm keys: %s\n", strings.Join(keys2, ", "))
+	}
+	fmt.Fprintf(w, "  landscape name: %s\n", name)
+	for _, key := range keys {
+		if strings.ToLower(key) != name {
+			fmt.Fprintf(w, "  names mapping: '%s' <=> '%s'\n", strings.ToLower(key), name)
+		}
+	}
+	for _, key := range keys {
+		explainProject(w, loader.ProjectsFile, key, in.Projects.Projects[key], in.PositionsP[strings.ToLower(key)])
+	}
+	if len(keys) == 0 {
+		fmt.Fprintf(w, "%s: missing\n", loader.ProjectsFile)
+	}
+	for _, key := range keys2 {
+		explainProject(w, loader.Projects2File, key, in.Projects2.Projects[key], in.PositionsP2[strings.ToLower(key)])
+	}
+	if len(keys2) == 0 {
+		fmt.Fprintf(w, "%s: missing\n", loader.Projects2File)
+	}
+	if len(occurrences) == 0 {
+		fmt.Fprintf(w, "%s: missing\n", in.LandscapeFormat)
+	}
+	for _, occurrence := range occurrences {
+		fmt.Fprintf(w, "%s: %s", in.LandscapeFormat, occurrence)
+	}
+	if in.CLOMonitor != nil {
+		explainCLOMonitor(w, c.Exceptions, in.CLOMonitor, r.Aliases, name)
+	}
+	if in.TOC != nil {
+		explainTOC(w, c.Exceptions, in.TOC, r.Aliases, name)
+	}
+	if in.Maintainers != nil {
+		explainMaintainers(w, c.Exceptions, in.Maintainers, r.Aliases, name)
+	}
+	if in.HelmValues != nil {
+		explainHelmValues(w, in.HelmValues, keys2)
+	}
+	if in.Website != nil {
+		explainWebsite(w, c.Exceptions, in.Website, r.Aliases, name, keys)
+	}
+	if in.AllRepoGroups != nil {
+		repos := []string{}
+		for _, key := range keys {
+			repos = append(repos, in.Projects.Projects[key].MainRepo)
+		}
+		explainAllCNCF(w, in.AllRepoGroups, repos)
+	}
+	fmt.Fprintf(w, "exceptions:\n")
+	applied := c.exceptionsOf(append(keys, keys2...), name)
+	if len(applied) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	for _, exception := range applied {
+		fmt.Fprintf(w, "  %s\n", exception)
+	}
+	fmt.Fprintf(w, "findings:\n")
+	found := 0
+	for _, f := range r.Findings {
+		if f.Project != name || f.Kind == model.KindStatusCount {
+			continue
+		}
+		fmt.Fprintf(w, "  %s (%s %s)\n", f.Message, f.Kind, f.Fingerprint)
+		found++
+	}
+	if found == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	return nil
+}
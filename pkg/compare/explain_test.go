@@ -0,0 +1,103 @@
+package compare_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// TestExplain checks explain output of testdata cases: resolved identity, source records, exceptions and findings
+func TestExplain(t *testing.T) {
+	for _, tc := range []struct {
+		dir   string
+		query string
+		want  []string
+		err   string
+	}{
+		{
+			dir:   "clomonitor",
+			query: " Renamed DevStats ",
+			want: []string{
+				"project: 'renamed landscape'\n  devstats keys: renamed\n  landscape name: renamed landscape\n  names mapping: 'renamed' <=> 'renamed landscape'\n",
+				"projects.yaml: 'renamed' [projects.yaml:25]\n  name: Renamed DevStats\n  status: Sandbox [projects.yaml:27]\n",
+				"  incubating_date: -\n",
+				"landscape.yml: Category / Subcategory: 'Renamed Landscape' [landscape.yml:30]\n",
+				"data/cncf.yaml: 'renamed' [data/cncf.yaml:23]\n  display_name: Renamed DevStats\n",
+				"exceptions:\n  rename: DevStats 'renamed devstats' is 'renamed landscape' in landscape\nfindings:\n  none\n",
+			},
+		},
+		{
+			dir:   "exceptions",
+			query: "Outdated",
+			want: []string{
+				"landscape.yml: Category / Subcategory: 'Outdated' [landscape.yml:15]\n  project: sandbox [landscape.yml:17]\n  repo_url: https://github.com/outdated/landscape-new [landscape.yml:16]\n",
+				"exceptions:\n  ignore repo: landscape 'outdated/landscape', DevStats 'outdated/devstats' are expected\n",
+				"(repo-exception-devstats ",
+				"(repo-exception-landscape ",
+			},
+		},
+		{
+			dir:   "exceptions",
+			query: "ignoreddates",
+			want:  []string{"  ignore join date: not compared\n  ignore incubating date: not compared\n  ignore graduated date: not compared\nfindings:\n  none\n"},
+		},
+		{
+			dir:   "in-sync",
+			query: "delta",
+			want:  []string{"projects.yaml: 'delta' [projects.yaml:30]\n", "devstats-helm/projects.yaml: missing\n", "exceptions:\n  none\n"},
+		},
+		{
+			dir:   "helm-values",
+			query: "alpha",
+			want:  []string{"devstats-helm/values.yaml: 'alpha' index 1 [devstats-helm/values.yaml:5]\n  ranges: provision [0, 7) inside, sync [1, 7) inside, cron [0, 6) inside\n"},
+		},
+		{
+			dir:   "website",
+			query: "alpha",
+			want:  []string{"apache/www/index_prod.html: 'Alpha' sandbox alphaproj [apache/www/index_prod.html:15]\n", "error: index page links to a wrong subdomain 'alpha'"},
+		},
+		{
+			dir:   "toc",
+			query: "ALPHA",
+			want:  []string{"README.md: 'Alpha' incubating [README.md:22]\n", "(toc-devstats ", "(toc-landscape "},
+		},
+		{
+			dir:   "maintainers",
+			query: "alpha",
+			want:  []string{"project-maintainers.csv: 'Alpha' Sandbox [project-maintainers.csv:2]\n"},
+		},
+		{
+			dir:   "all-cncf",
+			query: "alpha",
+			want:  []string{"scripts/all/repo_groups.sql: 'Alpha' alpha/alpha [scripts/all/repo_groups.sql:2]\n"},
+		},
+		{dir: "in-sync", query: "nope", err: "project 'nope' not found in any source"},
+	} {
+		in, err := loader.Load(loader.ReadDefault, testPaths(filepath.Join("testdata", tc.dir)))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.dir, err)
+		}
+		out := &bytes.Buffer{}
+		err = (&compare.Checker{Exceptions: testExceptions(), Policy: model.DefaultPolicy()}).Explain(out, in, tc.query)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s '%s': got error %v, want '%s'", tc.dir, tc.query, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s '%s': %v", tc.dir, tc.query, err)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s '%s': output does not contain %q:\n%s", tc.dir, tc.query, want, out.String())
+			}
+		}
+	}
+}
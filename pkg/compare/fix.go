@@ -0,0 +1,75 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+	"github.com/cncf/devstatscode"
+)
+
+// Fix is a suggested edit of devstats-helm projects.yaml, Key is the YAML key to set
+// Empty Key means the whole project defined at From should be added
+type Fix struct {
+	Pos     loader.SrcPos
+	From    loader.SrcPos
+	Project string
+	Key     string
+	Old     string
+	New     string
+}
+
+func (f Fix) String() string {
+	if f.Key == "" {
+		return fmt.Sprintf("%s: add project '%s'%s", f.Pos.File, f.Project, loader.Locs(f.From))
+	}
+	return fmt.Sprintf("%s: %s: %s: '%s' -> '%s'", f.Pos, f.Project, f.Key, f.Old, f.New)
+}
+
+// helmField is a compared field of a DevStats project: its YAML key, FieldPos key and value
+type helmField struct {
+	key   string
+	field string
+	value func(p devstatscode.Project) string
+}
+
+var helmFields = []helmField{
+	{key: "status", field: "status", value: func(p devstatscode.Project) string { return p.Status }},
+	{key: "main_repo", field: "repo", value: func(p devstatscode.Project) string { return p.MainRepo }},
+	{key: "join_date", field: "join", value: func(p devstatscode.Project) string { return normalize.DevstatsDate(p.JoinDate) }},
+	{key: "incubating_date", field: "incubating", value: func(p devstatscode.Project) string { return normalize.DevstatsDate(p.IncubatingDate) }},
+	{key: "graduated_date", field: "graduated", value: func(p devstatscode.Project) string { return normalize.DevstatsDate(p.GraduatedDate) }},
+}
+
+// Fixes returns edits making devstats-helm projects.yaml agree with devstats projects.yaml, which is the source of truth
+// for DevStats deployments, differences with landscape need a human decision so they are not suggested
+func (c *Checker) Fixes(in *loader.Inputs) []Fix {
+	fixes := []Fix{}
+	for _, key := range sortedKeys(in.Projects.Projects) {
+		name := strings.ToLower(key)
+		data := in.Projects.Projects[key]
+		_, skip := c.Exceptions.Skip[name]
+		if skip || data.Disabled {
+			continue
+		}
+		helm, ok := in.Projects2.Projects[key]
+		if !ok {
+			fixes = append(fixes, Fix{Pos: loader.SrcPos{File: loader.Projects2File}, From: in.PositionsP[name].Get("name"), Project: key})
+			continue
+		}
+		pos := in.PositionsP2[name]
+		for _, f := range helmFields {
+			want, got := f.value(data), f.value(helm)
+			if strings.TrimSpace(strings.ToLower(want)) == strings.TrimSpace(strings.ToLower(got)) {
+				continue
+			}
+			at := pos.Get(f.field)
+			if at.Line == 0 {
+				at = pos.Get("name")
+			}
+			fixes = append(fixes, Fix{Pos: at, Project: key, Key: f.key, Old: got, New: want})
+		}
+	}
+	return fixes
+}
@@ -0,0 +1,76 @@
+package compare
+
+import (
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// compareHelmValues checks devstats-helm values.yaml index ranges (when configured) against devstats-helm projects.yaml:
+// every enabled project must be listed in values and its index must be inside all ranges (provision, sync, cron),
+// while indexes inside any range must not point at disabled or missing projects
+func (s *state) compareHelmValues() {
+	v := s.in.HelmValues
+	if v == nil {
+		return
+	}
+	keys := make(map[string]string)
+	for key := range s.in.Projects2.Projects {
+		keys[strings.ToLower(key)] = key
+	}
+	indexes := make(map[string]int)
+	for i, proj := range v.Projects {
+		name := strings.ToLower(proj)
+		_, dup := indexes[name]
+		if !dup {
+			indexes[name] = i
+		}
+	}
+	errs := make(map[string]struct{})
+	for _, key := range sortedKeys(s.in.Projects2.Projects) {
+		data := s.in.Projects2.Projects[key]
+		name := strings.ToLower(key)
+		if s.skipped(name) || data.Disabled {
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		i, ok := indexes[name]
+		if !ok {
+			s.r.finding(model.KindHelmValues, fullName, "name", "error: devstats-helm project missing in values.yaml projects: '%s'%s\n", name, loader.Locs(s.in.PositionsP2[name].Get("name")))
+			errs[fullName] = struct{}{}
+			continue
+		}
+		for _, r := range v.Ranges {
+			if !r.Contains(i) {
+				s.r.finding(model.KindHelmValues, fullName, r.Name, "error: devstats-helm project index %d outside values.yaml %s range [%d, %d): '%s'%s\n", i, r.Name, r.From, r.To, name, loader.Locs(v.Pos[i].Get("name"), r.Pos.Get("from"), r.Pos.Get("to")))
+				errs[fullName] = struct{}{}
+			}
+		}
+	}
+	for i, proj := range v.Projects {
+		ranges := []string{}
+		for _, r := range v.Ranges {
+			if r.Contains(i) {
+				ranges = append(ranges, r.Name)
+			}
+		}
+		name := strings.ToLower(proj)
+		if len(ranges) == 0 || s.skipped(name) {
+			continue
+		}
+		key, ok := keys[name]
+		if !ok {
+			s.r.finding(model.KindHelmValues, name, "index", "error: values.yaml %s index %d points at project missing in devstats-helm projects: '%s'%s\n", strings.Join(ranges, "/"), i, name, loader.Locs(v.Pos[i].Get("name")))
+			errs[name] = struct{}{}
+			continue
+		}
+		data := s.in.Projects2.Projects[key]
+		if data.Disabled {
+			fullName := s.Exceptions.Name(data.FullName)
+			s.r.finding(model.KindHelmValues, fullName, "index", "error: values.yaml %s index %d points at disabled devstats-helm project: '%s'%s\n", strings.Join(ranges, "/"), i, name, loader.Locs(v.Pos[i].Get("name"), s.in.PositionsP2[name].Get("name")))
+			errs[fullName] = struct{}{}
+		}
+	}
+	s.r.summary(model.KindHelmValues, "error: values.yaml index ranges differences vs devstats-helm projects.yaml: %d\n", len(errs))
+}
@@ -0,0 +1,36 @@
+package compare
+
+import (
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// compareMaintainers checks CNCF project maintainers list (when configured) against DevStats projects: each listed
+// project must be in DevStats (unless disabled there) with the same status. Names are resolved like landscape ones
+func (s *state) compareMaintainers() {
+	if s.in.Maintainers == nil {
+		return
+	}
+	disabled := s.disabledNames()
+	errs := make(map[string]struct{})
+	for _, project := range s.in.Maintainers {
+		maturity := normalize.Status(project.Maturity)
+		name, ok := s.resolveAny(project.Name)
+		if !ok {
+			name = s.Exceptions.Name(project.Name)
+			_, off := disabled[name]
+			if !off {
+				s.r.finding(model.KindMaintainers, name, "name", "error: maintainers list %s project missing in devstats projects: '%s'%s\n", maturity, name, loader.Locs(project.Pos.Get("name")))
+				errs[name] = struct{}{}
+			}
+			continue
+		}
+		status := statusOf(s.p.byStatus, name)
+		if maturity != "" && maturity != status {
+			s.r.finding(model.KindMaintainers, name, "status", "error: devstats status not equal to maintainers list maturity '%s' '%s' <=> '%s'%s\n", name, status, maturity, loader.Locs(s.p.pos[name].Get("status"), project.Pos.Get("status")))
+			errs[name] = struct{}{}
+		}
+	}
+	s.r.summary(model.KindMaintainers, "error: maintainers list differences vs devstats projects.yaml: %d\n", len(errs))
+}
@@ -0,0 +1,8 @@
+-- All CNCF repo groups
+update gha_repos set repo_group = 'Alpha', alias = 'Alpha' where name in (
+  'alpha/alpha',
+  'alpha/docs'
+);
+update gha_repos set repo_group = 'Gamma', alias = 'Gamma' where org = 'beta';
+update gha_repos set repo_group = 'Renamed DevStats', alias = 'Renamed DevStats' where org in ('renamed', 'renamed-sigs');
+update gha_repos set repo_group = 'Delta', alias = 'Delta' where name = 'delta/delta';
@@ -0,0 +1,105 @@
+package compare
+
+import (
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// disabledNames returns DevStats keys and landscape names (lower case) of disabled DevStats projects
+func (s *state) disabledNames() map[string]struct{} {
+	disabled := make(map[string]struct{})
+	for key, data := range s.in.Projects.Projects {
+		if data.Disabled {
+			disabled[strings.ToLower(key)] = struct{}{}
+			disabled[s.Exceptions.Name(data.FullName)] = struct{}{}
+		}
+	}
+	return disabled
+}
+
+// compareTOC checks membership and maturity levels of DevStats and landscape projects against CNCF TOC projects list
+// (when configured). TOC projects are resolved to DevStats projects like CLOMonitor ones, disabled DevStats projects
+// and landscape entries that can be missing in DevStats (listed twice) are not reported, maturity is not compared for
+// projects whose status is ignored
+func (s *state) compareTOC() {
+	if s.in.TOC == nil {
+		return
+	}
+	disabled := s.disabledNames()
+	// CNCF projects in landscape (having a maturity level) by DevStats name when known, first listed maturity is used
+	statusL := make(map[string]string)
+	posL := make(map[string]loader.FieldPos)
+	for _, item := range s.in.Landscape {
+		status := normalize.Status(item.Project)
+		name := strings.ToLower(item.Name)
+		_, ignored := s.Exceptions.IgnoreMissing[name]
+		if status == "" || ignored {
+			continue
+		}
+		resolved, ok := s.resolveName(name)
+		if ok {
+			name = resolved
+		}
+		_, present := statusL[name]
+		if !present {
+			statusL[name] = status
+			posL[name] = item.Pos
+		}
+	}
+	errsP := make(map[string]struct{})
+	errsL := make(map[string]struct{})
+	listed := make(map[string]struct{})
+	for _, project := range s.in.TOC {
+		name, inDevstats := s.resolveAny(project.Name)
+		if !inDevstats {
+			name = s.Exceptions.Name(project.Name)
+		}
+		_, dup := listed[name]
+		if dup {
+			continue
+		}
+		listed[name] = struct{}{}
+		maturity := project.Maturity
+		_, off := disabled[name]
+		_, ignoreStatus := s.Exceptions.IgnoreStatus[name]
+		switch {
+		case inDevstats:
+			status := statusOf(s.p.byStatus, name)
+			if status != maturity && !ignoreStatus {
+				s.r.finding(model.KindTOCDevstats, name, "status", "error: devstats status not equal to toc maturity '%s' '%s' <=> '%s'%s\n", name, status, maturity, loader.Locs(s.p.pos[name].Get("status"), project.Pos.Get("status")))
+				errsP[name] = struct{}{}
+			}
+		case !off:
+			s.r.finding(model.KindTOCDevstats, name, "name", "error: toc %s project missing in devstats projects: '%s'%s\n", maturity, name, loader.Locs(project.Pos.Get("name")))
+			errsP[name] = struct{}{}
+		}
+		status, inLandscape := statusL[name]
+		switch {
+		case !inLandscape:
+			s.r.finding(model.KindTOCLandscape, name, "name", "error: toc %s project missing in landscape: '%s'%s\n", maturity, name, loader.Locs(project.Pos.Get("name")))
+			errsL[name] = struct{}{}
+		case status != maturity && !ignoreStatus:
+			s.r.finding(model.KindTOCLandscape, name, "status", "error: landscape maturity not equal to toc maturity '%s' '%s' <=> '%s'%s\n", name, status, maturity, loader.Locs(posL[name].Get("status"), project.Pos.Get("status")))
+			errsL[name] = struct{}{}
+		}
+	}
+	for _, name := range sortedKeys(s.projectsNames) {
+		_, ok := listed[name]
+		if !ok {
+			s.r.finding(model.KindTOCDevstats, name, "name", "error: devstats project not in toc projects list: '%s'%s\n", name, loader.Locs(s.p.pos[name].Get("name")))
+			errsP[name] = struct{}{}
+		}
+	}
+	for _, name := range sortedKeys(statusL) {
+		_, ok := listed[name]
+		if !ok {
+			s.r.finding(model.KindTOCLandscape, name, "name", "error: landscape %s project not in toc projects list: '%s'%s\n", statusL[name], name, loader.Locs(posL[name].Get("name")))
+			errsL[name] = struct{}{}
+		}
+	}
+	s.r.summary(model.KindTOCDevstats, "error: toc projects list differences vs devstats projects.yaml: %d\n", len(errsP))
+	s.r.summary(model.KindTOCLandscape, "error: toc projects list differences vs landscape: %d\n", len(errsL))
+}
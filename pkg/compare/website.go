@@ -0,0 +1,73 @@
+package compare
+
+import (
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// compareWebsite checks DevStats index page (when configured) against devstats projects.yaml: every enabled CNCF project
+// must be listed in its maturity section and link to its own subdomain. Listed projects are resolved by name like
+// landscape items, then by subdomain, disabled DevStats projects can still be listed (usually as archived)
+func (s *state) compareWebsite() {
+	if s.in.Website == nil {
+		return
+	}
+	disabled := s.disabledNames()
+	// DevStats keys by landscape name and landscape names by subdomain
+	keys := make(map[string]string)
+	bySubdomain := make(map[string]string)
+	for key, data := range s.in.Projects.Projects {
+		name := strings.ToLower(key)
+		if s.skipped(name) || data.Disabled {
+			continue
+		}
+		fullName := s.Exceptions.Name(data.FullName)
+		keys[fullName] = name
+		bySubdomain[normalize.Subdomain(name)] = fullName
+	}
+	errs := make(map[string]struct{})
+	listed := make(map[string]struct{})
+	for _, project := range s.in.Website {
+		name, ok := s.resolveAny(project.Name)
+		if !ok {
+			name, ok = bySubdomain[project.Subdomain]
+		}
+		if !ok {
+			name = s.Exceptions.Name(project.Name)
+			_, off := disabled[name]
+			_, offKey := disabled[project.Subdomain]
+			if !off && !offKey {
+				s.r.finding(model.KindWebsite, name, "name", "error: index page %s project missing in devstats projects: '%s' (%s)%s\n", project.Maturity, name, project.Subdomain, loader.Locs(project.Pos.Get("name")))
+				errs[name] = struct{}{}
+			}
+			continue
+		}
+		listed[name] = struct{}{}
+		status := statusOf(s.p.byStatus, name)
+		if status != project.Maturity {
+			s.r.finding(model.KindWebsite, name, "status", "error: devstats status not equal to index page section '%s' '%s' <=> '%s'%s\n", name, status, project.Maturity, loader.Locs(s.p.pos[name].Get("status"), project.Pos.Get("status")))
+			errs[name] = struct{}{}
+		}
+		subdomain := normalize.Subdomain(keys[name])
+		if subdomain != project.Subdomain {
+			s.r.finding(model.KindWebsite, name, "subdomain", "error: index page links to a wrong subdomain '%s' '%s' <=> '%s'%s\n", name, project.Subdomain, subdomain, loader.Locs(project.Pos.Get("subdomain")))
+			errs[name] = struct{}{}
+		}
+	}
+	for _, status := range sortedStatuses(s.p.byStatus) {
+		if !model.IsStatus(status) {
+			continue
+		}
+		for _, name := range sortedKeys(s.p.byStatus[status]) {
+			_, ok := listed[name]
+			if !ok {
+				s.r.finding(model.KindWebsite, name, "name", "error: devstats %s project missing in index page: '%s'%s\n", status, name, loader.Locs(s.p.pos[name].Get("name")))
+				errs[name] = struct{}{}
+			}
+		}
+	}
+	s.r.summary(model.KindWebsite, "error: index page differences vs devstats projects.yaml: %d\n", len(errs))
+}
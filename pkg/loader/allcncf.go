@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AllRepoGroupsFile is "All CNCF" repo groups definition path relative to cncf/devstats repository, also read when a
+// checkout is given
+const AllRepoGroupsFile = "scripts/all/repo_groups.sql"
+
+// RepoGroup is a single "All CNCF" repo group assignment: repos given by org/repo names and orgs
+type RepoGroup struct {
+	Name  string
+	Repos []string
+	Orgs  []string
+	Pos   SrcPos
+}
+
+// Elements of repo groups SQL statements, like "update gha_repos set repo_group = 'Name' where org in ('a', 'b')"
+var (
+	repoGroupRE = regexp.MustCompile(`(?is)\brepo_group\s*=\s*'([^']*)'`)
+	whereRE     = regexp.MustCompile(`(?is)\b(name|org)\s*(?:=\s*('[^']*')|in\s*\(([^)]*)\))`)
+	quotedRE    = regexp.MustCompile(`'([^']*)'`)
+)
+
+// ParseRepoGroups parses "All CNCF" repo groups SQL read from a given path, one group per update statement,
+// in the order they are listed (a repo assigned twice ends in the last group, like in the database)
+func ParseRepoGroups(path string, data []byte) ([]RepoGroup, error) {
+	groups := []RepoGroup{}
+	line := 1
+	for _, stmt := range strings.Split(string(data), ";") {
+		first := line
+		line += strings.Count(stmt, "\n")
+		m := repoGroupRE.FindStringSubmatchIndex(stmt)
+		if m == nil {
+			continue
+		}
+		// Location is the line setting the repo group, comments can precede the statement
+		start := first + strings.Count(stmt[:m[0]], "\n")
+		group := RepoGroup{Name: stmt[m[2]:m[3]], Repos: []string{}, Orgs: []string{}, Pos: SrcPos{File: AllRepoGroupsFile, Line: start}}
+		for _, where := range whereRE.FindAllStringSubmatch(stmt, -1) {
+			values := []string{}
+			for _, q := range quotedRE.FindAllStringSubmatch(where[2]+where[3], -1) {
+				values = append(values, strings.ToLower(strings.TrimSpace(q[1])))
+			}
+			if strings.ToLower(where[1]) == "org" {
+				group.Orgs = append(group.Orgs, values...)
+			} else {
+				group.Repos = append(group.Repos, values...)
+			}
+		}
+		if len(group.Repos) == 0 && len(group.Orgs) == 0 {
+			return nil, fmt.Errorf("'%s' line %d: repo group '%s' has no name or org condition", path, start, group.Name)
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no repo groups found in '%s'", path)
+	}
+	return groups, nil
+}
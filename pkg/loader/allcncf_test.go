@@ -0,0 +1,50 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseRepoGroups(t *testing.T) {
+	data := `-- comment
+update gha_repos set repo_group = 'Kubernetes', alias = 'Kubernetes'
+where org in ('Kubernetes', 'kubernetes-sigs');
+update gha_repos set repo_group = 'Prometheus' where name = 'prometheus/prometheus' or name in ('prometheus/alertmanager');
+select 1;
+`
+	groups, err := loader.ParseRepoGroups("repo_groups.sql", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	k, p := groups[0], groups[1]
+	if k.Name != "Kubernetes" || strings.Join(k.Orgs, ",") != "kubernetes,kubernetes-sigs" || len(k.Repos) != 0 || k.Pos.Line != 2 {
+		t.Errorf("wrong group: %+v", k)
+	}
+	if p.Name != "Prometheus" || strings.Join(p.Repos, ",") != "prometheus/prometheus,prometheus/alertmanager" || len(p.Orgs) != 0 || p.Pos.Line != 4 {
+		t.Errorf("wrong group: %+v", p)
+	}
+}
+
+func TestParseRepoGroupsMalformed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data string
+		err  string
+	}{
+		"empty":        {"", "no repo groups found"},
+		"no groups":    {"update gha_repos set alias = 'x' where org = 'x';\n", "no repo groups found"},
+		"not sql":      {"<html></html>", "no repo groups found"},
+		"no condition": {"select 1;\nupdate gha_repos set repo_group = 'Kubernetes';\n", "line 2: repo group 'Kubernetes' has no name or org condition"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			groups, err := loader.ParseRepoGroups("repo_groups.sql", []byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got %+v, error %v, want '%s'", groups, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// CLOMonitorFile is CNCF projects data file path relative to cncf/clomonitor repository, used when reporting locations
+const CLOMonitorFile = "data/cncf.yaml"
+
+// CLOMonitorProject is a single project of CLOMonitor data file
+// Name is CLOMonitor project ID, DisplayName is a human readable name (it is missing when equal to Name)
+type CLOMonitorProject struct {
+	Name        string
+	DisplayName string
+	Maturity    string
+	AcceptedAt  string
+	DevStatsURL string
+	Repos       []string
+	Pos         FieldPos
+}
+
+// cloMonitorYAML is CLOMonitor data file structure, only fields used by the sync check
+type cloMonitorYAML []struct {
+	Name         string `yaml:"name"`
+	DisplayName  string `yaml:"display_name"`
+	Maturity     string `yaml:"maturity"`
+	AcceptedAt   string `yaml:"accepted_at"`
+	DevStatsURL  string `yaml:"devstats_url"`
+	Repositories []struct {
+		Name string `yaml:"name"`
+		URL  string `yaml:"url"`
+	} `yaml:"repositories"`
+}
+
+// ParseCLOMonitor parses CLOMonitor data read from a given path, projects are in the order they are listed
+// It fails when no project has a name, so unrelated or empty data is not silently compared with nothing
+func ParseCLOMonitor(path string, data []byte) ([]CLOMonitorProject, error) {
+	var c cloMonitorYAML
+	err := yaml.Unmarshal(data, &c)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	positions, err := CLOMonitorPositions(CLOMonitorFile, data)
+	if err != nil {
+		return nil, fmt.Errorf("clomonitorPositions '%s' -> %+v", path, err)
+	}
+	projects := []CLOMonitorProject{}
+	named := 0
+	for i, project := range c {
+		if project.Name != "" {
+			named++
+		}
+		repos := []string{}
+		for _, repo := range project.Repositories {
+			repos = append(repos, repo.URL)
+		}
+		var pos FieldPos
+		if i < len(positions) {
+			pos = positions[i]
+		}
+		projects = append(projects, CLOMonitorProject{
+			Name:        project.Name,
+			DisplayName: project.DisplayName,
+			Maturity:    project.Maturity,
+			AcceptedAt:  project.AcceptedAt,
+			DevStatsURL: project.DevStatsURL,
+			Repos:       repos,
+			Pos:         pos,
+		})
+	}
+	if named == 0 {
+		return nil, fmt.Errorf("no projects found in '%s'", path)
+	}
+	return projects, nil
+}
+
+// CLOMonitorPositions returns locations of compared fields from CLOMonitor data file, indexed like projects in the file
+func CLOMonitorPositions(file string, data []byte) ([]FieldPos, error) {
+	root, err := rootNode(data)
+	if err != nil {
+		return nil, err
+	}
+	positions := []FieldPos{}
+	if root.Kind != yaml3.SequenceNode {
+		return positions, nil
+	}
+	for _, project := range root.Content {
+		pos := FieldPos{}
+		setPos(pos, file, project, "name", "name")
+		setPos(pos, file, project, "display_name", "full_name")
+		setPos(pos, file, project, "maturity", "status")
+		setPos(pos, file, project, "accepted_at", "join")
+		setPos(pos, file, project, "repositories", "repo")
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
@@ -0,0 +1,55 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseCLOMonitor(t *testing.T) {
+	data := `- name: alpha
+  display_name: Alpha Project
+  maturity: sandbox
+  accepted_at: "2020-01-01"
+  repositories:
+    - name: alpha
+      url: https://github.com/alpha/alpha
+- name: beta
+  maturity: incubating
+`
+	projects, err := loader.ParseCLOMonitor("cncf.yaml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+	a := projects[0]
+	if a.DisplayName != "Alpha Project" || a.AcceptedAt != "2020-01-01" || strings.Join(a.Repos, ",") != "https://github.com/alpha/alpha" {
+		t.Errorf("wrong project: %+v", a)
+	}
+	if a.Pos.Get("status").Line != 3 || projects[1].Pos.Get("name").Line != 8 {
+		t.Errorf("wrong locations: %+v, %+v", a.Pos, projects[1].Pos)
+	}
+}
+
+func TestParseCLOMonitorMalformed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data string
+		err  string
+	}{
+		"invalid yaml": {"- name: [", "yaml.Unmarshal"},
+		"not a list":   {"projects:\n  alpha: {}\n", "yaml.Unmarshal"},
+		"empty":        {"", "no projects found"},
+		"empty map":    {"{}\n", "yaml.Unmarshal"},
+		"no names":     {"- maturity: sandbox\n- title: Alpha\n", "no projects found"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.ParseCLOMonitor("cncf.yaml", []byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want %s error", err, tc.err)
+			}
+		})
+	}
+}
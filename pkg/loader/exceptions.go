@@ -0,0 +1,14 @@
+package loader
+
+import (
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// LoadExceptions reads exceptions store from a given url|path
+func LoadExceptions(path string) (*normalize.Store, error) {
+	data, err := ReadPathOrURL(path)
+	if err != nil {
+		return nil, err
+	}
+	return normalize.ParseStore(path, data)
+}
@@ -0,0 +1,190 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// GitCommit is a single commit that changed an input file
+// PR and Title come from merge commit subject and body or from squash merge "Title (#123)" subject
+type GitCommit struct {
+	SHA     string
+	Time    time.Time
+	Author  string
+	Subject string
+	PR      int
+	Title   string
+}
+
+// Subjects of GitHub merge and squash merge commits
+var (
+	mergeSubjectRE  = regexp.MustCompile(`^Merge pull request #(\d+) from \S+`)
+	squashSubjectRE = regexp.MustCompile(`^(.+) \(#(\d+)\)$`)
+)
+
+// newGitCommit parses a git log record, body is only used to get merged PR title
+func newGitCommit(sha, unix, author, subject, body string) (GitCommit, error) {
+	secs, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return GitCommit{}, err
+	}
+	c := GitCommit{SHA: sha, Time: time.Unix(secs, 0), Author: author, Subject: subject, Title: subject}
+	m := mergeSubjectRE.FindStringSubmatch(subject)
+	if m != nil {
+		c.PR, _ = strconv.Atoi(m[1])
+		for _, line := range strings.Split(body, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				c.Title = line
+				break
+			}
+		}
+		return c, nil
+	}
+	m = squashSubjectRE.FindStringSubmatch(subject)
+	if m != nil {
+		c.PR, _ = strconv.Atoi(m[2])
+		c.Title = m[1]
+	}
+	return c, nil
+}
+
+func (c *GitCommit) String() string {
+	title := c.Title
+	if c.PR > 0 {
+		title = fmt.Sprintf("PR #%d %s", c.PR, c.Title)
+	}
+	return fmt.Sprintf("%s %s %s: %s", model.ShortSHA(c.SHA), c.Time.UTC().Format("2006-01-02 15:04"), c.Author, title)
+}
+
+// GitSource is an input file in a local git clone of its upstream repository
+// Commits holds all commits changing that file, newest first
+type GitSource struct {
+	Source  string
+	Dir     string
+	File    string
+	Commits []GitCommit
+}
+
+// GitOutput runs a git command in a given directory and returns its output
+func GitOutput(dir string, args ...string) (string, error) {
+	return ExecCommandWithStdin(append([]string{"git", "-C", dir}, args...), bytes.NewBuffer(nil))
+}
+
+// Clones are local git clones of upstream repositories, used to check sync at past dates and to blame findings
+type Clones struct {
+	Landscape            string
+	Devstats             string
+	DevstatsDockerImages string
+}
+
+// ClonesFromEnv returns local clones from LANDSCAPE_REPO_PATH (cncf/landscape), DEVSTATS_REPO_PATH (cncf/devstats)
+// and DEVSTATS_DOCKER_IMAGES_REPO_PATH (cncf/devstats-docker-images)
+func ClonesFromEnv() Clones {
+	return Clones{
+		Landscape:            os.Getenv("LANDSCAPE_REPO_PATH"),
+		Devstats:             os.Getenv("DEVSTATS_REPO_PATH"),
+		DevstatsDockerImages: os.Getenv("DEVSTATS_DOCKER_IMAGES_REPO_PATH"),
+	}
+}
+
+// Dirs returns directories of all configured clones
+func (c Clones) Dirs() []string {
+	dirs := []string{}
+	for _, dir := range []string{c.Landscape, c.Devstats, c.DevstatsDockerImages} {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// GitSources returns input files from given local clones, all of them are required
+func GitSources(clones Clones) (map[string]*GitSource, error) {
+	sources := make(map[string]*GitSource)
+	for _, src := range []struct {
+		source string
+		dir    string
+		flag   string
+		file   string
+	}{
+		{source: model.SourceLandscape, dir: clones.Landscape, flag: "-landscape-repo (LANDSCAPE_REPO_PATH)", file: LandscapeFile},
+		{source: model.SourceDevstats, dir: clones.Devstats, flag: "-devstats-repo (DEVSTATS_REPO_PATH)", file: ProjectsFile},
+		{source: model.SourceDevstatsHelm, dir: clones.DevstatsDockerImages, flag: "-devstats-docker-images-repo (DEVSTATS_DOCKER_IMAGES_REPO_PATH)", file: Projects2File},
+	} {
+		if src.dir == "" {
+			return nil, fmt.Errorf("%s must point to a local clone to check sync at a given date", src.flag)
+		}
+		g := &GitSource{Source: src.source, Dir: src.dir, File: src.file}
+		err := g.loadCommits()
+		if err != nil {
+			return nil, err
+		}
+		sources[src.source] = g
+	}
+	return sources, nil
+}
+
+// loadCommits reads history of the source file, only following the main branch,
+// so merge commits (with PR titles) are reported instead of commits from merged branches
+func (g *GitSource) loadCommits() error {
+	out, err := GitOutput(g.Dir, "log", "--first-parent", "--format=%H%x1f%ct%x1f%an <%ae>%x1f%s%x1f%b%x1e", "HEAD", "--", g.File)
+	if err != nil {
+		return fmt.Errorf("git log '%s' in '%s': %v", g.File, g.Dir, err)
+	}
+	g.Commits = []GitCommit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		ary := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 5)
+		if len(ary) != 5 {
+			continue
+		}
+		commit, err := newGitCommit(ary[0], ary[1], ary[2], ary[3], ary[4])
+		if err != nil {
+			return fmt.Errorf("git log '%s' in '%s': invalid time in '%s'", g.File, g.Dir, ary[0])
+		}
+		g.Commits = append(g.Commits, commit)
+	}
+	return nil
+}
+
+// At returns the last commit changing the source file not later than a given time, nil if there is none
+func (g *GitSource) At(t time.Time) *GitCommit {
+	for i := range g.Commits {
+		if !g.Commits[i].Time.After(t) {
+			return &g.Commits[i]
+		}
+	}
+	return nil
+}
+
+// Show returns contents of the source file at a given commit
+func (g *GitSource) Show(sha string) ([]byte, error) {
+	out, err := GitOutput(g.Dir, "show", sha+":"+g.File)
+	if err != nil {
+		return nil, fmt.Errorf("git show %s:%s in '%s': %v", sha, g.File, g.Dir, err)
+	}
+	return []byte(out), nil
+}
+
+// ReadAt returns a Reader returning state of all sources at a given time
+func ReadAt(sources map[string]*GitSource, t time.Time) Reader {
+	return func(source, path string) ([]byte, string, error) {
+		g, ok := sources[source]
+		if !ok {
+			return nil, "", fmt.Errorf("no git clone for source '%s'", source)
+		}
+		commit := g.At(t)
+		if commit == nil {
+			return nil, "", fmt.Errorf("%s didn't exist in '%s' at %s", g.File, g.Dir, t.UTC().Format(time.RFC3339))
+		}
+		data, err := g.Show(commit.SHA)
+		return data, commit.SHA, err
+	}
+}
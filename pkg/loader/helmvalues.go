@@ -0,0 +1,104 @@
+package loader
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// HelmValuesFile is the chart values path relative to cncf/devstats-helm repository, used when reporting locations
+const HelmValuesFile = "devstats-helm/values.yaml"
+
+// HelmRanges are names of devstats-helm index ranges and their values keys (prefixes of From/To keys)
+var HelmRanges = []struct {
+	Name string
+	Key  string
+}{
+	{"provision", "indexProvisions"},
+	{"sync", "indexSyncs"},
+	{"cron", "indexCrons"},
+}
+
+// HelmRange is a devstats-helm index range, From is inclusive and To is exclusive (like in chart templates)
+// Unset From is 0 and unset To is the number of projects, so an unset range covers all projects, Pos has from and to keys
+type HelmRange struct {
+	Name string
+	From int
+	To   int
+	Pos  FieldPos
+}
+
+// Contains returns whether a given project index is inside the range
+func (r HelmRange) Contains(index int) bool {
+	return index >= r.From && index < r.To
+}
+
+// HelmValues holds devstats-helm values: projects (their proj names) in index order with their locations and all index ranges
+type HelmValues struct {
+	Projects []string
+	Ranges   []HelmRange
+	Pos      []FieldPos
+}
+
+// helmValuesYAML is devstats-helm values.yaml structure, only fields used by the sync check
+type helmValuesYAML struct {
+	Projects []struct {
+		Proj string `yaml:"proj"`
+	} `yaml:"projects"`
+}
+
+// ParseHelmValues parses devstats-helm values read from a given path, it fails when there are no projects
+func ParseHelmValues(path string, data []byte) (*HelmValues, error) {
+	var v helmValuesYAML
+	err := yaml.Unmarshal(data, &v)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	// Index keys are read separately, to know which ones are set
+	var keys map[string]interface{}
+	err = yaml.Unmarshal(data, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	root, err := rootNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("helmValuesPositions '%s' -> %+v", path, err)
+	}
+	values := &HelmValues{Projects: []string{}, Ranges: []HelmRange{}, Pos: []FieldPos{}}
+	for _, project := range v.Projects {
+		values.Projects = append(values.Projects, project.Proj)
+	}
+	if len(values.Projects) == 0 {
+		return nil, fmt.Errorf("no projects found in '%s'", path)
+	}
+	_, projects := mappingValue(root, "projects")
+	for i := range values.Projects {
+		pos := FieldPos{}
+		if projects != nil && projects.Kind == yaml3.SequenceNode && i < len(projects.Content) {
+			setPos(pos, HelmValuesFile, projects.Content[i], "proj", "name")
+		}
+		values.Pos = append(values.Pos, pos)
+	}
+	for _, r := range HelmRanges {
+		rng := HelmRange{Name: r.Name, From: 0, To: len(values.Projects), Pos: FieldPos{}}
+		for _, bound := range []struct {
+			suffix string
+			field  string
+			value  *int
+		}{{"From", "from", &rng.From}, {"To", "to", &rng.To}} {
+			value, ok := keys[r.Key+bound.suffix]
+			if !ok {
+				continue
+			}
+			n, ok := value.(int)
+			if !ok {
+				return nil, fmt.Errorf("'%s': %s%s is not an integer: %v", path, r.Key, bound.suffix, value)
+			}
+			*bound.value = n
+			setPos(rng.Pos, HelmValuesFile, root, r.Key+bound.suffix, bound.field)
+		}
+		values.Ranges = append(values.Ranges, rng)
+	}
+	return values, nil
+}
@@ -0,0 +1,59 @@
+package loader_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseHelmValues(t *testing.T) {
+	data := `projects:
+  - proj: alpha
+    db: alpha
+  - proj: beta
+indexSyncsFrom: 1
+indexCronsTo: 1
+`
+	values, err := loader.ParseHelmValues("values.yaml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(values.Projects, ",") != "alpha,beta" {
+		t.Errorf("got projects %v", values.Projects)
+	}
+	ranges := []string{}
+	for _, r := range values.Ranges {
+		ranges = append(ranges, fmt.Sprintf("%s[%d,%d)", r.Name, r.From, r.To))
+	}
+	if strings.Join(ranges, " ") != "provision[0,2) sync[1,2) cron[0,1)" {
+		t.Errorf("got ranges %v", ranges)
+	}
+	if !values.Ranges[0].Contains(1) || values.Ranges[1].Contains(0) || values.Ranges[2].Contains(1) {
+		t.Errorf("wrong range bounds: %+v", values.Ranges)
+	}
+	if values.Pos[1].Get("name").Line != 4 || values.Ranges[1].Pos.Get("from").Line != 5 || values.Ranges[1].Pos.Get("to").Line != 0 {
+		t.Errorf("wrong locations: %+v, %+v", values.Pos, values.Ranges[1].Pos)
+	}
+}
+
+func TestParseHelmValuesMalformed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data string
+		err  string
+	}{
+		"invalid yaml":      {"projects: [", "yaml.Unmarshal"},
+		"projects not list": {"projects: alpha\n", "yaml.Unmarshal"},
+		"no projects":       {"indexCronsTo: 3\n", "no projects found"},
+		"empty":             {"", "no projects found"},
+		"bound not integer": {"projects:\n  - proj: alpha\nindexCronsTo: all\n", "indexCronsTo is not an integer"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.ParseHelmValues("values.yaml", []byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want '%s'", err, tc.err)
+			}
+		})
+	}
+}
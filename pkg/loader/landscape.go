@@ -0,0 +1,193 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Landscape data formats, detected from contents
+// FormatLandscapeYAML is legacy landscape.yml, also used by landscape2 data.yml (which adds extra.archived and additional_repos)
+// FormatFullJSON is full.json generated by landscape2 build
+const (
+	FormatLandscapeYAML = "landscape.yml"
+	FormatFullJSON      = "full.json"
+)
+
+// LandscapeItem is a single landscape item, the same record is filled from all landscape formats
+// Project is the maturity level (sandbox, incubating, graduated, archived), dates are as given in the source
+// Pos holds locations of compared fields, they are only known for YAML formats
+type LandscapeItem struct {
+	Category        string
+	Subcategory     string
+	Name            string
+	RepoURL         string
+	AdditionalRepos []string
+	Project         string
+	Accepted        string
+	Incubating      string
+	Graduated       string
+	Archived        string
+	DevStatsURL     string
+	Pos             FieldPos
+}
+
+// landscapeYAML is landscape.yml/data.yml structure, only fields used by the sync check
+type landscapeYAML struct {
+	Landscape []struct {
+		Name          string `yaml:"name"`
+		Subcategories []struct {
+			Name  string `yaml:"name"`
+			Items []struct {
+				Name            string `yaml:"name"`
+				RepoURL         string `yaml:"repo_url"`
+				AdditionalRepos []struct {
+					RepoURL string `yaml:"repo_url"`
+				} `yaml:"additional_repos"`
+				Project string `yaml:"project"`
+				Extra   struct {
+					Accepted    string `yaml:"accepted"`
+					Incubating  string `yaml:"incubating"`
+					Graduated   string `yaml:"graduated"`
+					Archived    string `yaml:"archived"`
+					DevStatsURL string `yaml:"dev_stats_url"`
+				} `yaml:"extra"`
+			} `yaml:"items"`
+		} `yaml:"subcategories"`
+	} `yaml:"landscape"`
+}
+
+// fullJSON is landscape2 full.json structure, only fields used by the sync check
+type fullJSON struct {
+	Items []struct {
+		Category     string `json:"category"`
+		Subcategory  string `json:"subcategory"`
+		Name         string `json:"name"`
+		Maturity     string `json:"maturity"`
+		AcceptedAt   string `json:"accepted_at"`
+		IncubatingAt string `json:"incubating_at"`
+		GraduatedAt  string `json:"graduated_at"`
+		ArchivedAt   string `json:"archived_at"`
+		DevStatsURL  string `json:"devstats_url"`
+		Repositories []struct {
+			URL     string `json:"url"`
+			Primary bool   `json:"primary"`
+		} `json:"repositories"`
+	} `json:"items"`
+}
+
+// LandscapeFormat returns format of landscape data: JSON is landscape2 full.json, anything else is YAML
+func LandscapeFormat(data []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatFullJSON
+	}
+	return FormatLandscapeYAML
+}
+
+// ParseLandscape parses landscape data read from a given path in any supported format into items, in the order they are listed,
+// it fails when no items are found
+func ParseLandscape(path string, data []byte) ([]LandscapeItem, string, error) {
+	format := LandscapeFormat(data)
+	var (
+		items []LandscapeItem
+		err   error
+	)
+	switch format {
+	case FormatFullJSON:
+		items, err = parseFullJSON(path, data)
+	default:
+		items, err = parseLandscapeYAML(path, data)
+	}
+	// Unexpected root or an empty file would otherwise report all DevStats projects as missing in landscape
+	if err == nil && len(items) == 0 {
+		err = fmt.Errorf("no landscape items found in '%s' (%s)", path, format)
+	}
+	return items, format, err
+}
+
+// parseLandscapeYAML parses legacy landscape.yml or landscape2 data.yml
+func parseLandscapeYAML(path string, data []byte) ([]LandscapeItem, error) {
+	var l landscapeYAML
+	err := yaml.Unmarshal(data, &l)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	// Parse yaml into node tree too, to know where each compared value is located
+	positions, err := LandscapePositions(LandscapeFile, data)
+	if err != nil {
+		return nil, fmt.Errorf("landscapePositions '%s' -> %+v", path, err)
+	}
+	items := []LandscapeItem{}
+	for catIdx, cat := range l.Landscape {
+		for scatIdx, scat := range cat.Subcategories {
+			for itemIdx, item := range scat.Items {
+				repos := []string{}
+				for _, repo := range item.AdditionalRepos {
+					repos = append(repos, repo.RepoURL)
+				}
+				items = append(items, LandscapeItem{
+					Category:        cat.Name,
+					Subcategory:     scat.Name,
+					Name:            item.Name,
+					RepoURL:         item.RepoURL,
+					AdditionalRepos: repos,
+					Project:         item.Project,
+					Accepted:        item.Extra.Accepted,
+					Incubating:      item.Extra.Incubating,
+					Graduated:       item.Extra.Graduated,
+					Archived:        item.Extra.Archived,
+					DevStatsURL:     item.Extra.DevStatsURL,
+					Pos:             landscapeItemPos(positions, catIdx, scatIdx, itemIdx),
+				})
+			}
+		}
+	}
+	return items, nil
+}
+
+// parseFullJSON parses landscape2 full.json, primary repository (or the first one) is the item's repo
+func parseFullJSON(path string, data []byte) ([]LandscapeItem, error) {
+	var l fullJSON
+	err := json.Unmarshal(data, &l)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal '%s' -> %+v", path, err)
+	}
+	items := []LandscapeItem{}
+	for _, item := range l.Items {
+		primary := -1
+		for i, repo := range item.Repositories {
+			if repo.Primary {
+				primary = i
+				break
+			}
+		}
+		if primary < 0 && len(item.Repositories) > 0 {
+			primary = 0
+		}
+		repoURL := ""
+		repos := []string{}
+		for i, repo := range item.Repositories {
+			if i == primary {
+				repoURL = repo.URL
+				continue
+			}
+			repos = append(repos, repo.URL)
+		}
+		items = append(items, LandscapeItem{
+			Category:        item.Category,
+			Subcategory:     item.Subcategory,
+			Name:            item.Name,
+			RepoURL:         repoURL,
+			AdditionalRepos: repos,
+			Project:         item.Maturity,
+			Accepted:        item.AcceptedAt,
+			Incubating:      item.IncubatingAt,
+			Graduated:       item.GraduatedAt,
+			Archived:        item.ArchivedAt,
+			DevStatsURL:     item.DevStatsURL,
+		})
+	}
+	return items, nil
+}
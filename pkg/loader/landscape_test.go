@@ -0,0 +1,45 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseLandscape(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		data   string
+		format string
+		items  int
+		err    string
+	}{
+		{name: "yaml", data: "landscape:\n- name: C\n  subcategories:\n  - name: S\n    items:\n    - name: A\n      project: sandbox\n", format: loader.FormatLandscapeYAML, items: 1},
+		{name: "json", data: `{"items": [{"name": "A", "maturity": "sandbox", "repositories": [{"url": "https://github.com/a/a", "primary": true}]}]}`, format: loader.FormatFullJSON, items: 1},
+		{name: "empty", data: "", format: loader.FormatLandscapeYAML, err: "no landscape items found"},
+		{name: "unexpected root", data: "categories:\n- name: C\n", format: loader.FormatLandscapeYAML, err: "no landscape items found"},
+		{name: "empty json", data: `{"items": []}`, format: loader.FormatFullJSON, err: "no landscape items found"},
+		{name: "invalid yaml", data: "landscape: [", format: loader.FormatLandscapeYAML, err: "yaml.Unmarshal"},
+		{name: "invalid json", data: "{", format: loader.FormatFullJSON, err: "json.Unmarshal"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			items, format, err := loader.ParseLandscape("landscape.yml", []byte(tc.data))
+			if format != tc.format {
+				t.Errorf("format: got %s, want %s", format, tc.format)
+			}
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v, want '%s'", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != tc.items {
+				t.Errorf("got %d items, want %d", len(items), tc.items)
+			}
+		})
+	}
+}
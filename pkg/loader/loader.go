@@ -0,0 +1,276 @@
+// Package loader reads and parses sync check inputs: landscape.yml, devstats projects.yaml,
+// devstats-docker-images devstats-helm/projects.yaml, optional CLOMonitor data, CNCF TOC projects list, project
+// maintainers list, devstats-helm values.yaml, DevStats index page and "All CNCF" repo groups, from URLs, local files or local git clones, together with locations (file:line) of all compared values.
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstatscode"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ExecCommandWithStdin runs a command with a given stdin and returns its stdout
+func ExecCommandWithStdin(cmdAndArgs []string, stdIn *bytes.Buffer) (string, error) {
+	var (
+		stdOut bytes.Buffer
+		stdErr bytes.Buffer
+	)
+	command := cmdAndArgs[0]
+	arguments := cmdAndArgs[1:]
+	cmd := exec.Command(command, arguments...)
+	cmd.Stderr = &stdErr
+	cmd.Stdout = &stdOut
+	cmd.Stdin = stdIn
+	err := cmd.Start()
+	if err != nil {
+		return "", err
+	}
+	err = cmd.Wait()
+	if err != nil {
+		outStr := stdOut.String()
+		if len(outStr) > 0 {
+			fmt.Printf("STDOUT:\n%v\n", outStr)
+		}
+		errStr := stdErr.String()
+		if len(errStr) > 0 {
+			fmt.Printf("STDERR:\n%v\n", errStr)
+		}
+		return stdOut.String(), err
+	}
+	outStr := stdOut.String()
+	return outStr, nil
+}
+
+// ReadPathOrURL returns contents of a local file or of a http(s) URL
+func ReadPathOrURL(path string) ([]byte, error) {
+	if strings.Contains(path, "https://") || strings.Contains(path, "http://") {
+		response, err := http.Get(path)
+		if err != nil {
+			return nil, fmt.Errorf("http.Get '%s' -> %+v", path, err)
+		}
+		defer func() { _ = response.Body.Close() }()
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("http.Get '%s' -> %s", path, response.Status)
+		}
+		data, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("ioutil.ReadAll '%s' -> %+v", path, err)
+		}
+		return data, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ioutil.ReadFile: unable to read file '%s': %v", path, err)
+	}
+	return data, nil
+}
+
+// Reader returns data of a given source (model.Source*) and commit it was read from (if known)
+type Reader func(source, path string) ([]byte, string, error)
+
+// ReadDefault is a Reader reading local files or URLs
+func ReadDefault(source, path string) ([]byte, string, error) {
+	data, err := ReadPathOrURL(path)
+	return data, "", err
+}
+
+// Paths holds locations (path or URL) of all inputs, optional CLOMonitor data, TOC projects list, project
+// maintainers list, devstats-helm values, DevStats index page and "All CNCF" repo groups are not read when empty
+// Website and AllRepoGroups can also be a local cncf/devstats checkout, WebsiteFile or AllRepoGroupsFile is read from it then
+type Paths struct {
+	Landscape     string
+	Projects      string
+	Projects2     string
+	CLOMonitor    string
+	TOC           string
+	Maintainers   string
+	HelmValues    string
+	Website       string
+	AllRepoGroups string
+}
+
+// PathsFromEnv returns input locations from LANDSCAPE_YAML_PATH, PROJECTS_YAML_PATH, DOCKER_PROJECTS_YAML_PATH,
+// CLOMONITOR_YAML_PATH, TOC_MD_PATH, MAINTAINERS_CSV_PATH, HELM_VALUES_YAML_PATH, WEBSITE_HTML_PATH and
+// ALL_REPO_GROUPS_PATH, defaults are master branches of upstream repositories (optional sources have no default)
+func PathsFromEnv() Paths {
+	paths := Paths{
+		Landscape:     os.Getenv("LANDSCAPE_YAML_PATH"),
+		Projects:      os.Getenv("PROJECTS_YAML_PATH"),
+		Projects2:     os.Getenv("DOCKER_PROJECTS_YAML_PATH"),
+		CLOMonitor:    os.Getenv("CLOMONITOR_YAML_PATH"),
+		TOC:           os.Getenv("TOC_MD_PATH"),
+		Maintainers:   os.Getenv("MAINTAINERS_CSV_PATH"),
+		HelmValues:    os.Getenv("HELM_VALUES_YAML_PATH"),
+		Website:       os.Getenv("WEBSITE_HTML_PATH"),
+		AllRepoGroups: os.Getenv("ALL_REPO_GROUPS_PATH"),
+	}
+	if paths.Landscape == "" {
+		paths.Landscape = "https://raw.githubusercontent.com/cncf/landscape/master/landscape.yml"
+	}
+	if paths.Projects == "" {
+		paths.Projects = "https://raw.githubusercontent.com/cncf/devstats/master/projects.yaml"
+	}
+	if paths.Projects2 == "" {
+		paths.Projects2 = "https://raw.githubusercontent.com/cncf/devstats-docker-images/master/devstats-helm/projects.yaml"
+	}
+	return paths
+}
+
+// inCheckout returns path of a given file inside a local checkout when path is a directory, otherwise path itself
+func inCheckout(path, file string) string {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return filepath.Join(path, file)
+	}
+	return path
+}
+
+// Inputs holds all parsed inputs and locations of their compared values
+// Landscape items are read from any supported format (LandscapeFormat), PositionsP and PositionsP2 are keyed by lower case project name
+// CLOMonitor, TOC, Maintainers, HelmValues, Website and AllRepoGroups are nil when their optional sources are not configured
+type Inputs struct {
+	Landscape       []LandscapeItem
+	LandscapeFormat string
+	Projects        devstatscode.AllProjects
+	Projects2       devstatscode.AllProjects
+	CLOMonitor      []CLOMonitorProject
+	TOC             []TOCProject
+	Maintainers     []MaintainersProject
+	HelmValues      *HelmValues
+	Website         []WebsiteProject
+	AllRepoGroups   []RepoGroup
+	PositionsP      map[string]FieldPos
+	PositionsP2     map[string]FieldPos
+	Fetches         []model.FetchStat
+}
+
+// Load reads and parses all inputs, returned inputs always hold fetch stats, even when an error is returned
+func Load(read Reader, paths Paths) (*Inputs, error) {
+	in := &Inputs{Fetches: []model.FetchStat{}}
+	fetch := func(source, path string) ([]byte, error) {
+		dt := time.Now()
+		data, commit, err := read(source, path)
+		in.Fetches = append(in.Fetches, model.FetchStat{Source: source, Path: path, Commit: commit, Duration: time.Since(dt), Failed: err != nil, SHA256: model.Checksum(data)})
+		return data, err
+	}
+	dataL, err := fetch(model.SourceLandscape, paths.Landscape)
+	if err != nil {
+		return in, err
+	}
+	dataP, err := fetch(model.SourceDevstats, paths.Projects)
+	if err != nil {
+		return in, err
+	}
+	dataP2, err := fetch(model.SourceDevstatsHelm, paths.Projects2)
+	if err != nil {
+		return in, err
+	}
+	var dataC, dataT, dataM, dataH, dataW, dataA []byte
+	if paths.CLOMonitor != "" {
+		dataC, err = fetch(model.SourceCLOMonitor, paths.CLOMonitor)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.TOC != "" {
+		dataT, err = fetch(model.SourceTOC, paths.TOC)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.Maintainers != "" {
+		dataM, err = fetch(model.SourceMaintainers, paths.Maintainers)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.HelmValues != "" {
+		dataH, err = fetch(model.SourceHelmValues, paths.HelmValues)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.Website != "" {
+		paths.Website = inCheckout(paths.Website, WebsiteFile)
+		dataW, err = fetch(model.SourceWebsite, paths.Website)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.AllRepoGroups != "" {
+		paths.AllRepoGroups = inCheckout(paths.AllRepoGroups, AllRepoGroupsFile)
+		dataA, err = fetch(model.SourceAllRepoGroups, paths.AllRepoGroups)
+		if err != nil {
+			return in, err
+		}
+	}
+	// All inputs read
+	in.Landscape, in.LandscapeFormat, err = ParseLandscape(paths.Landscape, dataL)
+	if err != nil {
+		return in, err
+	}
+	err = yaml.Unmarshal(dataP, &in.Projects)
+	if err != nil {
+		return in, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", paths.Projects, err)
+	}
+	err = yaml.Unmarshal(dataP2, &in.Projects2)
+	if err != nil {
+		return in, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", paths.Projects2, err)
+	}
+	// Parse all yamls into node trees too, to know where each compared value is located
+	in.PositionsP, err = ProjectsPositions(ProjectsFile, dataP)
+	if err != nil {
+		return in, fmt.Errorf("projectsPositions '%s' -> %+v", paths.Projects, err)
+	}
+	in.PositionsP2, err = ProjectsPositions(Projects2File, dataP2)
+	if err != nil {
+		return in, fmt.Errorf("projectsPositions '%s' -> %+v", paths.Projects2, err)
+	}
+	if paths.CLOMonitor != "" {
+		in.CLOMonitor, err = ParseCLOMonitor(paths.CLOMonitor, dataC)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.TOC != "" {
+		in.TOC, err = ParseTOC(paths.TOC, dataT)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.Maintainers != "" {
+		in.Maintainers, err = ParseMaintainers(paths.Maintainers, dataM)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.HelmValues != "" {
+		in.HelmValues, err = ParseHelmValues(paths.HelmValues, dataH)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.Website != "" {
+		in.Website, err = ParseWebsite(paths.Website, dataW)
+		if err != nil {
+			return in, err
+		}
+	}
+	if paths.AllRepoGroups != "" {
+		in.AllRepoGroups, err = ParseRepoGroups(paths.AllRepoGroups, dataA)
+		if err != nil {
+			return in, err
+		}
+	}
+	return in, nil
+}
@@ -0,0 +1,79 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaintainersFile is the project maintainers list path relative to cncf/foundation repository, used when reporting locations
+const MaintainersFile = "project-maintainers.csv"
+
+// MaintainersProject is a single project of CNCF foundation project maintainers list
+type MaintainersProject struct {
+	Name     string
+	Maturity string
+	Pos      FieldPos
+}
+
+// ParseMaintainers parses CNCF project maintainers CSV read from a given path into projects, in the order they are listed
+// Columns are found by header: project and maturity (or status/level). Maintainer rows following a project row
+// usually leave both empty, they are skipped; each project is returned once, it fails when there are none
+func ParseMaintainers(path string, data []byte) ([]MaintainersProject, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv.Read '%s' -> %+v", path, err)
+	}
+	projectCol, maturityCol := -1, -1
+	for col, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case projectCol < 0 && strings.Contains(name, "project"):
+			projectCol = col
+		case maturityCol < 0 && (strings.Contains(name, "maturity") || strings.Contains(name, "status") || strings.Contains(name, "level")):
+			maturityCol = col
+		}
+	}
+	if projectCol < 0 || maturityCol < 0 {
+		return nil, fmt.Errorf("'%s': project and maturity columns not found in header: %s", path, strings.Join(header, ","))
+	}
+	projects := []MaintainersProject{}
+	seen := make(map[string]struct{})
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv.Read '%s' -> %+v", path, err)
+		}
+		if projectCol >= len(record) || maturityCol >= len(record) {
+			continue
+		}
+		name, maturity := strings.TrimSpace(record[projectCol]), strings.TrimSpace(record[maturityCol])
+		if name == "" {
+			continue
+		}
+		_, dup := seen[strings.ToLower(name)]
+		if dup {
+			continue
+		}
+		seen[strings.ToLower(name)] = struct{}{}
+		line, _ := r.FieldPos(projectCol)
+		statusLine, _ := r.FieldPos(maturityCol)
+		projects = append(projects, MaintainersProject{
+			Name:     name,
+			Maturity: maturity,
+			Pos:      FieldPos{"name": SrcPos{File: MaintainersFile, Line: line}, "status": SrcPos{File: MaintainersFile, Line: statusLine}},
+		})
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects found in '%s'", path)
+	}
+	return projects, nil
+}
@@ -0,0 +1,53 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseMaintainers(t *testing.T) {
+	data := `Maturity Level,Project,Name,Company,GitHub Name
+Graduated,Alpha,Alice,Example,alice
+,,Bob,Example,bob
+Sandbox,"Beta, Inc",Carol,Other,carol
+Sandbox,alpha,Dan,Example,dan
+short
+`
+	projects, err := loader.ParseMaintainers("project-maintainers.csv", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, p := range projects {
+		got = append(got, p.Name+":"+p.Maturity)
+	}
+	want := "Alpha:Graduated|Beta, Inc:Sandbox"
+	if strings.Join(got, "|") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, "|"), want)
+	}
+	if projects[1].Pos.Get("name").Line != 4 {
+		t.Errorf("wrong location: %+v", projects[1].Pos)
+	}
+}
+
+func TestParseMaintainersMalformed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data string
+		err  string
+	}{
+		"empty":             {"", "csv.Read"},
+		"no maturity":       {"Project,Name\nAlpha,Alice\n", "project and maturity columns not found"},
+		"no project column": {"Status,Name\nSandbox,Alice\n", "project and maturity columns not found"},
+		"header only":       {"Project,Status\n", "no projects found"},
+		"broken quotes":     {"Project,Status\n\"Alpha,Sandbox\n", "no projects found"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.ParseMaintainers("project-maintainers.csv", []byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want '%s'", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// LoadPolicy reads severity policy from a given url|path, empty path means severity.yaml which can be missing
+func LoadPolicy(path string) (*model.Policy, error) {
+	optional := path == ""
+	if optional {
+		path = "severity.yaml"
+	}
+	policy := model.DefaultPolicy()
+	if optional {
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return policy, nil
+		}
+	}
+	data, err := ReadPathOrURL(path)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, policy)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	err = policy.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return policy, nil
+}
@@ -0,0 +1,161 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// File names used when reporting locations, they are paths relative to the upstream repositories:
+// cncf/landscape, cncf/devstats and cncf/devstats-docker-images
+const (
+	LandscapeFile = "landscape.yml"
+	ProjectsFile  = "projects.yaml"
+	Projects2File = "devstats-helm/projects.yaml"
+)
+
+// SrcPos is a location of a value in one of the input YAML files
+type SrcPos struct {
+	File string
+	Line int
+}
+
+func (p SrcPos) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// FieldPos holds locations of compared fields of a single project record
+// Keys are: name, full_name, repo, join, incubating, graduated, archived, status, disabled
+type FieldPos map[string]SrcPos
+
+func (f FieldPos) Get(field string) SrcPos {
+	if f == nil {
+		return SrcPos{}
+	}
+	return f[field]
+}
+
+// Locs returns a " [file:line, ...]" suffix for all known locations, or an empty string
+func Locs(refs ...SrcPos) string {
+	strs := []string{}
+	for _, ref := range refs {
+		if ref.Line > 0 {
+			strs = append(strs, ref.String())
+		}
+	}
+	if len(strs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(strs, ", ") + "]"
+}
+
+// mappingValue returns key and value nodes for a given key in a mapping node
+func mappingValue(node *yaml3.Node, key string) (*yaml3.Node, *yaml3.Node) {
+	if node == nil || node.Kind != yaml3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// rootNode parses YAML data into a node tree and returns its top level node
+func rootNode(data []byte) (*yaml3.Node, error) {
+	var doc yaml3.Node
+	err := yaml3.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind == yaml3.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0], nil
+	}
+	return &doc, nil
+}
+
+// setPos stores location of a given YAML key's value (if present) as a given field
+func setPos(pos FieldPos, file string, node *yaml3.Node, key, field string) {
+	_, value := mappingValue(node, key)
+	if value != nil {
+		pos[field] = SrcPos{File: file, Line: value.Line}
+	}
+}
+
+// ProjectsPositions returns locations of compared fields from devstats projects.yaml file
+// Result is keyed by lower case project name (the key used under "projects:")
+func ProjectsPositions(file string, data []byte) (map[string]FieldPos, error) {
+	root, err := rootNode(data)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[string]FieldPos)
+	_, projects := mappingValue(root, "projects")
+	if projects == nil || projects.Kind != yaml3.MappingNode {
+		return positions, nil
+	}
+	for i := 0; i+1 < len(projects.Content); i += 2 {
+		key, node := projects.Content[i], projects.Content[i+1]
+		pos := FieldPos{"name": SrcPos{File: file, Line: key.Line}}
+		setPos(pos, file, node, "name", "full_name")
+		setPos(pos, file, node, "main_repo", "repo")
+		setPos(pos, file, node, "join_date", "join")
+		setPos(pos, file, node, "incubating_date", "incubating")
+		setPos(pos, file, node, "graduated_date", "graduated")
+		setPos(pos, file, node, "status", "status")
+		setPos(pos, file, node, "disabled", "disabled")
+		positions[strings.ToLower(key.Value)] = pos
+	}
+	return positions, nil
+}
+
+// LandscapePositions returns locations of compared fields from landscape.yml file
+// Result is indexed the same way as items in the file: [category][subcategory][item]
+func LandscapePositions(file string, data []byte) ([][][]FieldPos, error) {
+	root, err := rootNode(data)
+	if err != nil {
+		return nil, err
+	}
+	positions := [][][]FieldPos{}
+	_, categories := mappingValue(root, "landscape")
+	if categories == nil || categories.Kind != yaml3.SequenceNode {
+		return positions, nil
+	}
+	for _, category := range categories.Content {
+		catPos := [][]FieldPos{}
+		_, subcategories := mappingValue(category, "subcategories")
+		if subcategories != nil {
+			for _, subcategory := range subcategories.Content {
+				scatPos := []FieldPos{}
+				_, items := mappingValue(subcategory, "items")
+				if items != nil {
+					for _, item := range items.Content {
+						pos := FieldPos{}
+						setPos(pos, file, item, "name", "name")
+						setPos(pos, file, item, "repo_url", "repo")
+						setPos(pos, file, item, "project", "status")
+						_, extra := mappingValue(item, "extra")
+						setPos(pos, file, extra, "accepted", "join")
+						setPos(pos, file, extra, "incubating", "incubating")
+						setPos(pos, file, extra, "graduated", "graduated")
+						setPos(pos, file, extra, "archived", "archived")
+						scatPos = append(scatPos, pos)
+					}
+				}
+				catPos = append(catPos, scatPos)
+			}
+		}
+		positions = append(positions, catPos)
+	}
+	return positions, nil
+}
+
+// landscapeItemPos returns locations for a given landscape item, handles missing indices
+func landscapeItemPos(positions [][][]FieldPos, cat, scat, item int) FieldPos {
+	if cat >= len(positions) || scat >= len(positions[cat]) || item >= len(positions[cat][scat]) {
+		return nil
+	}
+	return positions[cat][scat][item]
+}
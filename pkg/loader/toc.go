@@ -0,0 +1,118 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// TOCFile is the CNCF TOC projects list path relative to cncf/toc repository, used when reporting locations
+const TOCFile = "README.md"
+
+// TOCProject is a single project listed in CNCF TOC markdown tables
+type TOCProject struct {
+	Name     string
+	Maturity string
+	Pos      FieldPos
+}
+
+// Markdown elements of TOC project tables
+var (
+	headingRE   = regexp.MustCompile(`^#+\s+(.*)$`)
+	separatorRE = regexp.MustCompile(`^:?-+:?$`)
+	linkRE      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	htmlRE      = regexp.MustCompile(`<[^>]+>`)
+)
+
+// tableCells splits a markdown table row into trimmed cells, returns nil when a line is not a table row
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "|") {
+		return nil
+	}
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// cellText returns plain text of a markdown table cell: link texts without links, emphasis and HTML tags
+func cellText(cell string) string {
+	cell = linkRE.ReplaceAllString(cell, "$1")
+	cell = htmlRE.ReplaceAllString(cell, "")
+	cell = strings.NewReplacer("**", "", "__", "", "`", "").Replace(cell)
+	return strings.TrimSpace(cell)
+}
+
+// maturityOf returns the first maturity level mentioned in a given text, or an empty string
+func maturityOf(text string) string {
+	text = strings.ToLower(text)
+	for _, status := range model.Statuses {
+		if strings.Contains(text, status) {
+			return status
+		}
+	}
+	return ""
+}
+
+// ParseTOC parses CNCF TOC markdown read from a given path: each table having a project (or name) column lists projects,
+// their maturity comes from a maturity (level, stage or status) column or from the closest heading above the table
+func ParseTOC(path string, data []byte) ([]TOCProject, error) {
+	projects := []TOCProject{}
+	lines := strings.Split(string(data), "\n")
+	headingMaturity := ""
+	nameCol, maturityCol := -1, -1
+	inTable := false
+	for i, line := range lines {
+		m := headingRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m != nil {
+			headingMaturity = maturityOf(m[1])
+			inTable = false
+			continue
+		}
+		cells := tableCells(line)
+		if cells == nil {
+			inTable = false
+			continue
+		}
+		if !inTable {
+			// Header row starts a new table
+			inTable = true
+			nameCol, maturityCol = -1, -1
+			for col, cell := range cells {
+				header := strings.ToLower(cellText(cell))
+				switch {
+				case nameCol < 0 && (strings.Contains(header, "project") || header == "name"):
+					nameCol = col
+				case maturityCol < 0 && (strings.Contains(header, "maturity") || strings.Contains(header, "level") || strings.Contains(header, "stage") || header == "status"):
+					maturityCol = col
+				}
+			}
+			continue
+		}
+		if nameCol < 0 || nameCol >= len(cells) || separatorRE.MatchString(strings.ReplaceAll(cells[0], " ", "")) {
+			continue
+		}
+		name := cellText(cells[nameCol])
+		if name == "" {
+			continue
+		}
+		maturity := headingMaturity
+		if maturityCol >= 0 && maturityCol < len(cells) {
+			maturity = maturityOf(cellText(cells[maturityCol]))
+		}
+		if maturity == "" {
+			continue
+		}
+		pos := SrcPos{File: TOCFile, Line: i + 1}
+		projects = append(projects, TOCProject{Name: name, Maturity: maturity, Pos: FieldPos{"name": pos, "status": pos}})
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no project tables found in '%s'", path)
+	}
+	return projects, nil
+}
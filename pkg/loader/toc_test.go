@@ -0,0 +1,59 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseTOC(t *testing.T) {
+	data := `# CNCF TOC
+
+## Graduated Projects
+
+| Project | Accepted |
+|---------|----------|
+| [Alpha](https://alpha.io) | 2020 |
+| **Beta** | 2021 |
+
+## Other projects
+
+| Name | Maturity Level |
+|:-----|:---:|
+| Gamma | Incubating |
+| Delta | unknown |
+| | Sandbox |
+`
+	projects, err := loader.ParseTOC("README.md", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, p := range projects {
+		got = append(got, p.Name+":"+p.Maturity)
+	}
+	want := "Alpha:graduated Beta:graduated Gamma:incubating"
+	if strings.Join(got, " ") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, " "), want)
+	}
+	if projects[0].Pos.Get("name").Line != 7 || projects[2].Pos.Get("status").Line != 14 {
+		t.Errorf("wrong locations: %+v, %+v", projects[0].Pos, projects[2].Pos)
+	}
+}
+
+func TestParseTOCMalformed(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":             "",
+		"no tables":         "# Graduated\n\nAlpha, Beta\n",
+		"no project column": "## Graduated\n\n| Repo | Accepted |\n|---|---|\n| alpha/alpha | 2020 |\n",
+		"no maturity":       "## Projects\n\n| Project | Accepted |\n|---|---|\n| Alpha | 2020 |\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.ParseTOC("README.md", []byte(data))
+			if err == nil || !strings.Contains(err.Error(), "no project tables found") {
+				t.Errorf("got error %v, want no project tables found", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WebsiteFile is the DevStats index page path relative to cncf/devstats repository, also read when a checkout is given
+const WebsiteFile = "apache/www/index_prod.html"
+
+// WebsiteProject is a single project linked from DevStats index page
+// Subdomain is the first label of the linked DevStats host, Maturity is the section the project is listed in
+type WebsiteProject struct {
+	Name      string
+	Subdomain string
+	Maturity  string
+	Pos       FieldPos
+}
+
+// HTML elements of DevStats index page
+var (
+	devstatsLinkRE = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']https?://([a-z0-9-]+)\.(?:[a-z0-9-]+\.)*(?:devstats|teststats)\.[a-z0-9.-]+[^"']*["'][^>]*>(.*?)</a>`)
+	altRE          = regexp.MustCompile(`(?i)\salt\s*=\s*["']([^"']*)["']`)
+)
+
+// sectionMaturity returns the maturity level of a section heading line: a short text without DevStats links mentioning
+// a maturity level (like "<h2>Graduated projects</h2>"), or an empty string
+func sectionMaturity(line string) string {
+	text := strings.TrimSpace(htmlRE.ReplaceAllString(line, ""))
+	if text == "" || len(text) > 40 || devstatsLinkRE.MatchString(line) {
+		return ""
+	}
+	return maturityOf(text)
+}
+
+// ParseWebsite parses DevStats index page read from a given path: projects are DevStats links (by subdomain), their
+// maturity is the section heading above them. Image links are named by their alt text, each subdomain is returned once
+func ParseWebsite(path string, data []byte) ([]WebsiteProject, error) {
+	projects := []WebsiteProject{}
+	bySubdomain := make(map[string]int)
+	maturity := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		section := sectionMaturity(line)
+		if section != "" {
+			maturity = section
+			continue
+		}
+		for _, m := range devstatsLinkRE.FindAllStringSubmatch(line, -1) {
+			subdomain := strings.ToLower(m[1])
+			name := strings.TrimSpace(htmlRE.ReplaceAllString(m[2], ""))
+			if name == "" {
+				alt := altRE.FindStringSubmatch(m[2])
+				if alt != nil {
+					name = strings.TrimSpace(alt[1])
+				}
+			}
+			idx, ok := bySubdomain[subdomain]
+			if ok {
+				if projects[idx].Name == "" {
+					projects[idx].Name = name
+				}
+				continue
+			}
+			if maturity == "" {
+				continue
+			}
+			bySubdomain[subdomain] = len(projects)
+			pos := SrcPos{File: WebsiteFile, Line: i + 1}
+			projects = append(projects, WebsiteProject{Name: name, Subdomain: subdomain, Maturity: maturity, Pos: FieldPos{"name": pos, "status": pos, "subdomain": pos}})
+		}
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects found in '%s'", path)
+	}
+	return projects, nil
+}
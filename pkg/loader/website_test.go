@@ -0,0 +1,51 @@
+package loader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+func TestParseWebsite(t *testing.T) {
+	data := `<html><body>
+<p><a href="https://all.devstats.cncf.io">All CNCF</a></p>
+<h2>Graduated projects</h2>
+<tr><td><a href="https://k8s.devstats.cncf.io/"><img src="k8s.png" alt="Kubernetes"></a></td><td><a href="https://k8s.devstats.cncf.io/">Kubernetes</a></td></tr>
+<tr><td><a href='https://Prometheus.teststats.cncf.io/d/8'><img src="p.png" alt="Prometheus"></a></td></tr>
+<h2>Sandbox</h2>
+<tr><td><a href="https://k8s.devstats.cncf.io/">Kubernetes again</a></td><td><a href="https://github.com/cncf/devstats">Source</a></td></tr>
+</body></html>
+`
+	projects, err := loader.ParseWebsite("index_prod.html", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, p := range projects {
+		got = append(got, p.Name+":"+p.Subdomain+":"+p.Maturity)
+	}
+	want := "Kubernetes:k8s:graduated|Prometheus:prometheus:graduated"
+	if strings.Join(got, "|") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, "|"), want)
+	}
+	if projects[1].Pos.Get("subdomain").Line != 5 {
+		t.Errorf("wrong location: %+v", projects[1].Pos)
+	}
+}
+
+func TestParseWebsiteMalformed(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":             "",
+		"no sections":       `<a href="https://k8s.devstats.cncf.io/">Kubernetes</a>`,
+		"no devstats links": "<h2>Graduated</h2>\n<a href=\"https://kubernetes.io/\">Kubernetes</a>\n",
+		"not html":          "{\"projects\": []}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.ParseWebsite("index_prod.html", []byte(data))
+			if err == nil || !strings.Contains(err.Error(), "no projects found") {
+				t.Errorf("got error %v, want no projects found", err)
+			}
+		})
+	}
+}
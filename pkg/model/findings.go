@@ -0,0 +1,170 @@
+// Package model holds types shared by all other packages: findings reported by comparisons,
+// their categories and check kinds, and the report of a single sync check run.
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+// Finding categories, each comparison pass reports into its own category
+const (
+	CatInput            = "input"
+	CatDocker           = "docker"
+	CatMissingDevstats  = "missing-devstats"
+	CatMissingLandscape = "missing-landscape"
+	CatRepo             = "repo"
+	CatJoin             = "join"
+	CatIncubating       = "incubating"
+	CatGraduated        = "graduated"
+	CatStatus           = "status"
+	CatCLOMonitor       = "clomonitor"
+	CatTOC              = "toc"
+	CatMaintainers      = "maintainers"
+	CatHelmValues       = "helm-values"
+	CatWebsite          = "website"
+	CatAllCNCF          = "all-cncf"
+)
+
+// Categories lists all finding categories in the order they are reported
+var Categories = []string{
+	CatInput,
+	CatDocker,
+	CatMissingDevstats,
+	CatMissingLandscape,
+	CatRepo,
+	CatJoin,
+	CatIncubating,
+	CatGraduated,
+	CatStatus,
+	CatCLOMonitor,
+	CatTOC,
+	CatMaintainers,
+	CatHelmValues,
+	CatWebsite,
+	CatAllCNCF,
+}
+
+// CategoryTitles holds human readable names of finding categories
+var CategoryTitles = map[string]string{
+	CatInput:            "Input errors",
+	CatDocker:           "devstats-helm vs devstats projects.yaml",
+	CatMissingDevstats:  "Missing in DevStats",
+	CatMissingLandscape: "Missing in landscape",
+	CatRepo:             "Main repositories",
+	CatJoin:             "Join dates",
+	CatIncubating:       "Incubating dates",
+	CatGraduated:        "Graduated dates",
+	CatStatus:           "Maturity levels",
+	CatCLOMonitor:       "CLOMonitor vs devstats projects.yaml",
+	CatTOC:              "CNCF TOC projects list",
+	CatMaintainers:      "CNCF project maintainers list",
+	CatHelmValues:       "devstats-helm values.yaml index ranges",
+	CatWebsite:          "DevStats website index page",
+	CatAllCNCF:          "All CNCF repo groups",
+}
+
+// Check kinds, each kind is a single check reporting into one category
+const (
+	KindInput                  = "input"
+	KindDockerInDevstats       = "docker-in-devstats"
+	KindDevstatsInDocker       = "devstats-in-docker"
+	KindMissingDevstats        = "missing-devstats"
+	KindMissingLandscape       = "missing-landscape"
+	KindRepo                   = "repo"
+	KindRepoExceptionLandscape = "repo-exception-landscape"
+	KindRepoExceptionDevstats  = "repo-exception-devstats"
+	KindJoin                   = "join"
+	KindIncubating             = "incubating"
+	KindGraduated              = "graduated"
+	KindStatus                 = "status"
+	KindStatusCount            = "status-count"
+	KindCLOMonitor             = "clomonitor"
+	KindTOCDevstats            = "toc-devstats"
+	KindTOCLandscape           = "toc-landscape"
+	KindMaintainers            = "maintainers"
+	KindHelmValues             = "helm-values"
+	KindWebsite                = "website"
+	KindAllCNCF                = "all-cncf"
+)
+
+// KindCategories maps check kinds to finding categories
+var KindCategories = map[string]string{
+	KindInput:                  CatInput,
+	KindDockerInDevstats:       CatDocker,
+	KindDevstatsInDocker:       CatDocker,
+	KindMissingDevstats:        CatMissingDevstats,
+	KindMissingLandscape:       CatMissingLandscape,
+	KindRepo:                   CatRepo,
+	KindRepoExceptionLandscape: CatRepo,
+	KindRepoExceptionDevstats:  CatRepo,
+	KindJoin:                   CatJoin,
+	KindIncubating:             CatIncubating,
+	KindGraduated:              CatGraduated,
+	KindStatus:                 CatStatus,
+	KindStatusCount:            CatStatus,
+	KindCLOMonitor:             CatCLOMonitor,
+	KindTOCDevstats:            CatTOC,
+	KindTOCLandscape:           CatTOC,
+	KindMaintainers:            CatMaintainers,
+	KindHelmValues:             CatHelmValues,
+	KindWebsite:                CatWebsite,
+	KindAllCNCF:                CatAllCNCF,
+}
+
+// Finding is a single detected problem, Message is the line that is also printed to stdout
+// Fingerprint identifies the problem across runs, it doesn't depend on the values compared
+// Severity comes from the policy of the check kind
+type Finding struct {
+	Fingerprint string `json:"fingerprint"`
+	Kind        string `json:"kind"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Project     string `json:"project"`
+	Field       string `json:"field"`
+	Message     string `json:"message"`
+	Blame       *Blame `json:"blame,omitempty"`
+}
+
+// NewFinding returns a finding of a given check kind and severity
+func NewFinding(kind, severity, project, field, message string) Finding {
+	return Finding{
+		Fingerprint: Fingerprint(kind, project, field),
+		Kind:        kind,
+		Category:    KindCategories[kind],
+		Severity:    severity,
+		Project:     project,
+		Field:       field,
+		Message:     message,
+	}
+}
+
+// Fingerprint returns a stable finding identifier
+func Fingerprint(kind, project, field string) string {
+	hash := sha1.Sum([]byte(kind + "\x00" + project + "\x00" + field))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+// FindingsGroup holds all findings of a single category
+type FindingsGroup struct {
+	Category string
+	Title    string
+	Findings []Finding
+}
+
+// GroupFindings groups findings by category, only non-empty groups are returned
+func GroupFindings(findings []Finding) []FindingsGroup {
+	byCategory := make(map[string][]Finding)
+	for _, f := range findings {
+		byCategory[f.Category] = append(byCategory[f.Category], f)
+	}
+	groups := []FindingsGroup{}
+	for _, category := range Categories {
+		items, ok := byCategory[category]
+		if !ok {
+			continue
+		}
+		groups = append(groups, FindingsGroup{Category: category, Title: CategoryTitles[category], Findings: items})
+	}
+	return groups
+}
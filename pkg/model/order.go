@@ -0,0 +1,66 @@
+package model
+
+import "sort"
+
+// Statuses lists maturity levels in lifecycle order
+var Statuses = []string{"sandbox", "incubating", "graduated", "archived"}
+
+// Fields lists compared fields in the order they are checked
+var Fields = []string{"name", "status", "repo", "join", "incubating", "graduated"}
+
+// rank returns index of a value in a given order, unknown values are ranked after all known ones
+func rank(order []string, value string) int {
+	for i, v := range order {
+		if v == value {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// IsStatus returns whether a given status is a known maturity level
+func IsStatus(status string) bool {
+	return rank(Statuses, status) < len(Statuses)
+}
+
+// SortStatuses sorts maturity levels in lifecycle order, unknown ones go last in alphabetical order
+func SortStatuses(statuses []string) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		ri, rj := rank(Statuses, statuses[i]), rank(Statuses, statuses[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return statuses[i] < statuses[j]
+	})
+}
+
+// fieldRank orders fields, status count findings use maturity levels as fields so they are ranked in lifecycle order
+func fieldRank(field string) int {
+	r := rank(Fields, field)
+	if r == len(Fields) {
+		r += rank(Statuses, field)
+	}
+	return r
+}
+
+// SortFindings sorts findings by category (in reporting order), project, field and kind
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		fi, fj := findings[i], findings[j]
+		ci, cj := rank(Categories, fi.Category), rank(Categories, fj.Category)
+		if ci != cj {
+			return ci < cj
+		}
+		if fi.Project != fj.Project {
+			return fi.Project < fj.Project
+		}
+		ri, rj := fieldRank(fi.Field), fieldRank(fj.Field)
+		if ri != rj {
+			return ri < rj
+		}
+		if fi.Field != fj.Field {
+			return fi.Field < fj.Field
+		}
+		return fi.Kind < fj.Kind
+	})
+}
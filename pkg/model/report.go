@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Report is the result of a single sync check, it is passed to all notifiers
+// New holds findings that were not reported by the previous run
+// Unfiltered holds all findings of a report filtered by notifier's minimum severity (nil when not filtered)
+// Aliases maps DevStats project names to landscape names and vice versa
+type Report struct {
+	Started    time.Time         `json:"started"`
+	Finished   time.Time         `json:"finished"`
+	Error      string            `json:"error,omitempty"`
+	Msgs       []string          `json:"-"`
+	Findings   []Finding         `json:"findings"`
+	New        []Finding         `json:"new"`
+	Unfiltered []Finding         `json:"-"`
+	Aliases    map[string]string `json:"-"`
+	// Per source input fetch stats and project counts per maturity level (for metrics)
+	Fetches      []FetchStat               `json:"fetches"`
+	StatusCounts map[string]map[string]int `json:"status_counts"`
+}
+
+// CategoryCounts returns number of findings per category
+func (r *Report) CategoryCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, f := range r.Findings {
+		counts[f.Category]++
+	}
+	return counts
+}
+
+// Input sources
+const (
+	SourceLandscape     = "landscape"
+	SourceDevstats      = "devstats"
+	SourceDevstatsHelm  = "devstats-helm"
+	SourceCLOMonitor    = "clomonitor"
+	SourceTOC           = "toc"
+	SourceMaintainers   = "maintainers"
+	SourceHelmValues    = "helm-values"
+	SourceWebsite       = "website"
+	SourceAllRepoGroups = "all-repo-groups"
+)
+
+// Sources lists all input sources, required ones first
+var Sources = []string{
+	SourceLandscape, SourceDevstats, SourceDevstatsHelm, SourceCLOMonitor, SourceTOC, SourceMaintainers,
+	SourceHelmValues, SourceWebsite, SourceAllRepoGroups,
+}
+
+// FetchStat holds how long reading a given source took and if it failed
+type FetchStat struct {
+	Source   string        `json:"source"`
+	Path     string        `json:"path"`
+	Commit   string        `json:"commit,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Failed   bool          `json:"failed"`
+	SHA256   string        `json:"sha256,omitempty"`
+}
+
+// Checksum returns hex encoded SHA-256 of given data, empty for no data
+func Checksum(data []byte) string {
+	if data == nil {
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Blame is the upstream commit that introduced a finding
+type Blame struct {
+	File   string `json:"file"`
+	SHA    string `json:"sha"`
+	Author string `json:"author"`
+	Time   string `json:"time"`
+	PR     int    `json:"pr,omitempty"`
+	Title  string `json:"title"`
+}
+
+// ShortSHA returns the first 8 characters of a commit SHA, shorter (abbreviated or empty) SHAs are returned unchanged
+func ShortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+func (b *Blame) String() string {
+	title := b.Title
+	if b.PR > 0 {
+		title = fmt.Sprintf("PR #%d %s", b.PR, b.Title)
+	}
+	return fmt.Sprintf("introduced by %s %s (%s, %s): %s", b.File, ShortSHA(b.SHA), b.Author, b.Time, title)
+}
@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+func TestShortSHA(t *testing.T) {
+	for sha, want := range map[string]string{
+		"0123456789abcdef": "01234567",
+		"01234567":         "01234567",
+		"0123":             "0123",
+		"":                 "",
+	} {
+		got := model.ShortSHA(sha)
+		if got != want {
+			t.Errorf("ShortSHA(%q): got %q, want %q", sha, got, want)
+		}
+	}
+	b := &model.Blame{File: "projects.yaml", SHA: "abc", Author: "a", Time: "t", Title: "x"}
+	if b.String() != "introduced by projects.yaml abc (a, t): x" {
+		t.Errorf("unexpected blame: %s", b.String())
+	}
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities of findings, off means a check kind is not reported at all
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+// Severities lists all severities, most severe first
+var Severities = []string{SeverityError, SeverityWarning, SeverityInfo, SeverityOff}
+
+// SeverityRank returns how severe a given severity is, higher is more severe, unknown is -1
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return len(Severities) - 1 - i
+		}
+	}
+	return -1
+}
+
+// AtLeast returns whether severity is at least as severe as min
+func AtLeast(severity, min string) bool {
+	return SeverityRank(severity) >= SeverityRank(min)
+}
+
+// Notifiers lists names of all notifiers, policy can set the minimum severity of findings each of them gets
+var Notifiers = []string{"email", "slack", "webhook", "github"}
+
+// Policy maps check kinds to severities (default: error) and notifiers to the minimum severity
+// of findings they get (default: warning, so info findings are only printed and recorded)
+type Policy struct {
+	Kinds     map[string]string `yaml:"kinds"`
+	Notifiers map[string]string `yaml:"notifiers"`
+}
+
+// DefaultPolicy returns policy reporting all kinds as errors
+func DefaultPolicy() *Policy {
+	return &Policy{Kinds: map[string]string{}, Notifiers: map[string]string{}}
+}
+
+// Validate checks that only known kinds, notifiers and severities are used
+func (p *Policy) Validate() error {
+	for kind, severity := range p.Kinds {
+		_, ok := KindCategories[kind]
+		if !ok {
+			return fmt.Errorf("unknown check kind '%s'", kind)
+		}
+		if SeverityRank(severity) < 0 {
+			return fmt.Errorf("kind '%s': unknown severity '%s', allowed: %s", kind, severity, strings.Join(Severities, ", "))
+		}
+	}
+	for name, severity := range p.Notifiers {
+		known := false
+		for _, notifier := range Notifiers {
+			if notifier == name {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown notifier '%s', allowed: %s", name, strings.Join(Notifiers, ", "))
+		}
+		if SeverityRank(severity) < 0 || severity == SeverityOff {
+			return fmt.Errorf("notifier '%s': unknown severity '%s', allowed: %s", name, severity, strings.Join(Severities[:3], ", "))
+		}
+	}
+	return nil
+}
+
+// Severity returns severity of a given check kind
+func (p *Policy) Severity(kind string) string {
+	if p != nil {
+		severity, ok := p.Kinds[kind]
+		if ok {
+			return severity
+		}
+	}
+	return SeverityError
+}
+
+// NotifierSeverity returns the minimum severity of findings a given notifier gets
+func (p *Policy) NotifierSeverity(name string) string {
+	if p != nil {
+		severity, ok := p.Notifiers[name]
+		if ok {
+			return severity
+		}
+	}
+	return SeverityWarning
+}
+
+// SeverityCounts returns number of findings per severity
+func SeverityCounts(findings []Finding) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+// SeveritySummary returns number of findings per severity, like "3 errors, 5 warnings", empty when no findings
+func SeveritySummary(findings []Finding) string {
+	counts := SeverityCounts(findings)
+	parts := []string{}
+	for _, severity := range Severities[:3] {
+		count := counts[severity]
+		if count == 0 {
+			continue
+		}
+		name := severity
+		if count > 1 && severity != SeverityInfo {
+			name += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", count, name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FilterSeverity returns findings at least as severe as min
+func FilterSeverity(findings []Finding, min string) []Finding {
+	filtered := []Finding{}
+	for _, f := range findings {
+		if AtLeast(f.Severity, min) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
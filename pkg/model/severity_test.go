@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy model.Policy
+		err    string
+	}{
+		{name: "default", policy: *model.DefaultPolicy()},
+		{name: "valid", policy: model.Policy{Kinds: map[string]string{model.KindRepo: model.SeverityWarning, model.KindStatusCount: model.SeverityOff}, Notifiers: map[string]string{"email": model.SeverityInfo, "github": model.SeverityError}}},
+		{name: "unknown kind", policy: model.Policy{Kinds: map[string]string{"colour": model.SeverityError}}, err: "unknown check kind 'colour'"},
+		{name: "unknown kind severity", policy: model.Policy{Kinds: map[string]string{model.KindRepo: "fatal"}}, err: "kind 'repo': unknown severity 'fatal'"},
+		{name: "unknown notifier", policy: model.Policy{Notifiers: map[string]string{"emial": model.SeverityInfo}}, err: "unknown notifier 'emial', allowed: email, slack, webhook, github"},
+		{name: "notifier off", policy: model.Policy{Notifiers: map[string]string{"slack": model.SeverityOff}}, err: "notifier 'slack': unknown severity 'off'"},
+	} {
+		err := tc.policy.Validate()
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: got error %v, want '%s'", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package normalize maps DevStats and landscape values to comparable forms: project names (including known
+// renames), repositories, maturity levels and dates, and holds exceptions: known and accepted differences.
+package normalize
+
+// Exceptions holds known differences between DevStats and landscape that should not be reported
+// DevstatsToLandscape maps DevStats full names to landscape names, Skip lists DevStats projects not compared at all,
+// IgnoreMissing lists landscape projects that can be missing in DevStats,
+// IgnoreRepo maps landscape names to expected landscape and DevStats repos, Ignore*Date and IgnoreStatus
+// list projects whose dates or maturity levels are not compared
+type Exceptions struct {
+	DevstatsToLandscape  map[string]string
+	Skip                 map[string]struct{}
+	IgnoreMissing        map[string]struct{}
+	IgnoreRepo           map[string][2]string
+	IgnoreJoinDate       map[string]struct{}
+	IgnoreIncubatingDate map[string]struct{}
+	IgnoreGraduatedDate  map[string]struct{}
+	IgnoreStatus         map[string]struct{}
+}
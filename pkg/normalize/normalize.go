@@ -0,0 +1,64 @@
+package normalize
+
+import (
+	"strings"
+	"time"
+)
+
+// Name returns lower case DevStats project full name, mapped to landscape name when it is a known rename
+func (e *Exceptions) Name(fullName string) string {
+	fullName = strings.ToLower(fullName)
+	mapped, ok := e.DevstatsToLandscape[fullName]
+	if ok {
+		fullName = mapped
+	}
+	return strings.ToLower(fullName)
+}
+
+// Repo returns lower case org/repo
+func Repo(repo string) string {
+	return strings.TrimSpace(strings.ToLower(repo))
+}
+
+// RepoURL returns lower case org/repo from a GitHub repository URL
+func RepoURL(url string) string {
+	repo := strings.Replace(strings.TrimSpace(strings.ToLower(url)), "https://github.com/", "", -1)
+	return strings.Replace(strings.TrimSpace(strings.ToLower(repo)), "http://github.com/", "", -1)
+}
+
+// subdomains are DevStats subdomains not equal to project keys
+var subdomains = map[string]string{
+	"kubernetes": "k8s",
+}
+
+// Subdomain returns DevStats website subdomain of a given DevStats project key
+func Subdomain(key string) string {
+	key = strings.ToLower(key)
+	subdomain, ok := subdomains[key]
+	if ok {
+		return subdomain
+	}
+	return key
+}
+
+// Status returns lower case maturity level
+func Status(status string) string {
+	return strings.TrimSpace(strings.ToLower(status))
+}
+
+// Date returns YYYY-MM-DD date from a landscape date string, which can also contain time
+func Date(dt string) string {
+	dtS := strings.TrimSpace(dt)
+	if len(dtS) > 10 {
+		dtS = dtS[:10]
+	}
+	return dtS
+}
+
+// DevstatsDate returns YYYY-MM-DD date of a DevStats date, empty when not set
+func DevstatsDate(dt *time.Time) string {
+	if dt == nil {
+		return ""
+	}
+	return dt.Format("2006-01-02")
+}
@@ -0,0 +1,255 @@
+package normalize
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Exception types, as used in exception IDs (type/project) and command line
+const (
+	TypeRename               = "rename"
+	TypeSkip                 = "skip"
+	TypeIgnoreMissing        = "ignore-missing"
+	TypeIgnoreRepo           = "ignore-repo"
+	TypeIgnoreJoinDate       = "ignore-join-date"
+	TypeIgnoreIncubatingDate = "ignore-incubating-date"
+	TypeIgnoreGraduatedDate  = "ignore-graduated-date"
+	TypeIgnoreStatus         = "ignore-status"
+)
+
+// Types lists all exception types in the order they are stored
+var Types = []string{
+	TypeRename,
+	TypeSkip,
+	TypeIgnoreMissing,
+	TypeIgnoreRepo,
+	TypeIgnoreJoinDate,
+	TypeIgnoreIncubatingDate,
+	TypeIgnoreGraduatedDate,
+	TypeIgnoreStatus,
+}
+
+// Exception is a single entry of the exceptions store
+// Project is a DevStats name for rename and skip entries and a landscape name otherwise
+// Landscape and DevStats hold expected values: landscape name for renames, both repos for ignored repos
+type Exception struct {
+	Project   string `yaml:"project"`
+	Landscape string `yaml:"landscape,omitempty"`
+	DevStats  string `yaml:"devstats,omitempty"`
+	Reason    string `yaml:"reason"`
+}
+
+// Store is the exceptions store (exceptions.yaml), entries of each type are kept sorted by project
+type Store struct {
+	Rename               []Exception `yaml:"rename"`
+	Skip                 []Exception `yaml:"skip"`
+	IgnoreMissing        []Exception `yaml:"ignore_missing"`
+	IgnoreRepo           []Exception `yaml:"ignore_repo"`
+	IgnoreJoinDate       []Exception `yaml:"ignore_join_date"`
+	IgnoreIncubatingDate []Exception `yaml:"ignore_incubating_date"`
+	IgnoreGraduatedDate  []Exception `yaml:"ignore_graduated_date"`
+	IgnoreStatus         []Exception `yaml:"ignore_status"`
+}
+
+// storeHeader is written at the top of the exceptions store
+const storeHeader = `# Known and accepted differences between DevStats and landscape, they are not reported by check_sync.
+# Please edit using 'check_sync exceptions add|remove', entries of each type are sorted by project.
+# rename: DevStats full name (project) has a different landscape name (landscape).
+# skip: DevStats project (project key) is not compared at all.
+# ignore_missing: landscape project can be missing in DevStats (for example it is listed twice in landscape).
+# ignore_repo: landscape project has expected landscape and DevStats repos that differ.
+# ignore_*_date, ignore_status: landscape project's dates or maturity level are not compared.
+`
+
+// entries returns entries of a given exception type
+func (s *Store) entries(typ string) (*[]Exception, error) {
+	switch typ {
+	case TypeRename:
+		return &s.Rename, nil
+	case TypeSkip:
+		return &s.Skip, nil
+	case TypeIgnoreMissing:
+		return &s.IgnoreMissing, nil
+	case TypeIgnoreRepo:
+		return &s.IgnoreRepo, nil
+	case TypeIgnoreJoinDate:
+		return &s.IgnoreJoinDate, nil
+	case TypeIgnoreIncubatingDate:
+		return &s.IgnoreIncubatingDate, nil
+	case TypeIgnoreGraduatedDate:
+		return &s.IgnoreGraduatedDate, nil
+	case TypeIgnoreStatus:
+		return &s.IgnoreStatus, nil
+	}
+	return nil, fmt.Errorf("unknown exception type '%s', allowed: %s", typ, strings.Join(Types, ", "))
+}
+
+// ParseID splits an exception ID (type/project) into type and lower case project
+func ParseID(id string) (string, string, error) {
+	ary := strings.SplitN(id, "/", 2)
+	if len(ary) != 2 || ary[1] == "" {
+		return "", "", fmt.Errorf("invalid exception ID '%s', expected type/project", id)
+	}
+	return ary[0], strings.ToLower(strings.TrimSpace(ary[1])), nil
+}
+
+// ID returns exception ID: type/project
+func ID(typ, project string) string {
+	return typ + "/" + project
+}
+
+// Get returns exception of a given type and project, nil when there is none
+func (s *Store) Get(typ, project string) (*Exception, error) {
+	list, err := s.entries(typ)
+	if err != nil {
+		return nil, err
+	}
+	project = strings.ToLower(strings.TrimSpace(project))
+	for i := range *list {
+		if (*list)[i].Project == project {
+			return &(*list)[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Put adds an exception of a given type or replaces an existing one of the same project, returns true when replaced
+func (s *Store) Put(typ string, e Exception) (bool, error) {
+	e.Project = strings.ToLower(strings.TrimSpace(e.Project))
+	e.Reason = strings.TrimSpace(e.Reason)
+	if e.Project == "" {
+		return false, fmt.Errorf("exception project is required")
+	}
+	if e.Reason == "" {
+		return false, fmt.Errorf("exception reason is required")
+	}
+	list, err := s.entries(typ)
+	if err != nil {
+		return false, err
+	}
+	existing, _ := s.Get(typ, e.Project)
+	if existing != nil {
+		*existing = e
+		return true, nil
+	}
+	*list = append(*list, e)
+	sort.SliceStable(*list, func(i, j int) bool { return (*list)[i].Project < (*list)[j].Project })
+	return false, nil
+}
+
+// Remove removes exception of a given type and project, returns false when there is none
+func (s *Store) Remove(typ, project string) (bool, error) {
+	list, err := s.entries(typ)
+	if err != nil {
+		return false, err
+	}
+	project = strings.ToLower(strings.TrimSpace(project))
+	for i, e := range *list {
+		if e.Project == project {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Each calls a given function for all exceptions, in stored order
+func (s *Store) Each(fn func(typ string, e Exception)) {
+	for _, typ := range Types {
+		list, _ := s.entries(typ)
+		for _, e := range *list {
+			fn(typ, e)
+		}
+	}
+}
+
+// Exceptions returns exceptions used by comparisons
+func (s *Store) Exceptions() *Exceptions {
+	e := &Exceptions{
+		DevstatsToLandscape:  make(map[string]string),
+		Skip:                 make(map[string]struct{}),
+		IgnoreMissing:        make(map[string]struct{}),
+		IgnoreRepo:           make(map[string][2]string),
+		IgnoreJoinDate:       make(map[string]struct{}),
+		IgnoreIncubatingDate: make(map[string]struct{}),
+		IgnoreGraduatedDate:  make(map[string]struct{}),
+		IgnoreStatus:         make(map[string]struct{}),
+	}
+	sets := map[string]map[string]struct{}{
+		TypeSkip:                 e.Skip,
+		TypeIgnoreMissing:        e.IgnoreMissing,
+		TypeIgnoreJoinDate:       e.IgnoreJoinDate,
+		TypeIgnoreIncubatingDate: e.IgnoreIncubatingDate,
+		TypeIgnoreGraduatedDate:  e.IgnoreGraduatedDate,
+		TypeIgnoreStatus:         e.IgnoreStatus,
+	}
+	s.Each(func(typ string, ex Exception) {
+		switch typ {
+		case TypeRename:
+			e.DevstatsToLandscape[ex.Project] = ex.Landscape
+		case TypeIgnoreRepo:
+			e.IgnoreRepo[ex.Project] = [2]string{ex.Landscape, ex.DevStats}
+		default:
+			sets[typ][ex.Project] = struct{}{}
+		}
+	})
+	return e
+}
+
+// validate checks that projects are lower case and unique within each type and that all entries have reasons
+func (s *Store) validate() error {
+	var err error
+	seen := make(map[string]struct{})
+	s.Each(func(typ string, e Exception) {
+		id := ID(typ, e.Project)
+		_, dup := seen[id]
+		seen[id] = struct{}{}
+		switch {
+		case err != nil:
+		case e.Project == "" || e.Project != strings.ToLower(strings.TrimSpace(e.Project)):
+			err = fmt.Errorf("%s: project must be a non-empty lower case name", id)
+		case dup:
+			err = fmt.Errorf("%s: duplicate exception", id)
+		case strings.TrimSpace(e.Reason) == "":
+			err = fmt.Errorf("%s: reason is required", id)
+		case typ == TypeRename && e.Landscape == "":
+			err = fmt.Errorf("%s: landscape name is required", id)
+		}
+	})
+	return err
+}
+
+// ParseStore parses exceptions store data, path is only used in error messages
+func ParseStore(path string, data []byte) (*Store, error) {
+	store := &Store{}
+	err := yaml.UnmarshalStrict(data, store)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	err = store.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return store, nil
+}
+
+// Save writes the exceptions store to a local file
+func (s *Store) Save(path string) error {
+	for _, typ := range Types {
+		list, _ := s.entries(typ)
+		sort.SliceStable(*list, func(i, j int) bool { return (*list)[i].Project < (*list)[j].Project })
+	}
+	err := s.validate()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append([]byte(storeHeader), data...), 0644)
+}
@@ -0,0 +1,130 @@
+package normalize
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ids returns IDs and reasons of all exceptions of a store, in stored order
+func ids(s *Store) []string {
+	list := []string{}
+	s.Each(func(typ string, e Exception) {
+		list = append(list, ID(typ, e.Project)+": "+e.Landscape+" "+e.DevStats+" "+e.Reason)
+	})
+	return list
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	s := &Store{}
+	for _, tc := range []struct {
+		typ      string
+		e        Exception
+		replaced bool
+	}{
+		{TypeSkip, Exception{Project: "zeta", Reason: "not a project"}, false},
+		{TypeSkip, Exception{Project: " Alpha ", Reason: "test only"}, false},
+		{TypeRename, Exception{Project: "old name", Landscape: "New Name", Reason: "renamed"}, false},
+		{TypeIgnoreRepo, Exception{Project: "beta", Landscape: "beta/landscape", DevStats: "beta/devstats", Reason: "moved"}, false},
+		{TypeSkip, Exception{Project: "alpha", Reason: " still test only "}, true},
+	} {
+		replaced, err := s.Put(tc.typ, tc.e)
+		if err != nil {
+			t.Fatalf("Put(%s, %+v): %v", tc.typ, tc.e, err)
+		}
+		if replaced != tc.replaced {
+			t.Errorf("Put(%s, %+v) replaced = %v, want %v", tc.typ, tc.e, replaced, tc.replaced)
+		}
+	}
+	want := []Exception{{Project: "alpha", Reason: "still test only"}, {Project: "zeta", Reason: "not a project"}}
+	if !reflect.DeepEqual(s.Skip, want) {
+		t.Errorf("skip entries = %+v, want %+v", s.Skip, want)
+	}
+	removed, err := s.Remove(TypeSkip, "ZETA")
+	if err != nil || !removed {
+		t.Errorf("Remove(skip, ZETA) = %v, %v, want true", removed, err)
+	}
+	removed, err = s.Remove(TypeSkip, "zeta")
+	if err != nil || removed {
+		t.Errorf("Remove(skip, zeta) again = %v, %v, want false", removed, err)
+	}
+	path := filepath.Join(t.TempDir(), "exceptions.yaml")
+	err = s.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), storeHeader) {
+		t.Errorf("saved store does not start with the header:\n%s", data)
+	}
+	loaded, err := ParseStore(path, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids(loaded), ids(s)) {
+		t.Errorf("loaded exceptions = %v, want %v", ids(loaded), ids(s))
+	}
+	e := loaded.Exceptions()
+	if e.DevstatsToLandscape["old name"] != "New Name" || e.IgnoreRepo["beta"] != [2]string{"beta/landscape", "beta/devstats"} {
+		t.Errorf("unexpected exceptions: %+v", e)
+	}
+	if _, ok := e.Skip["zeta"]; ok {
+		t.Errorf("removed exception still used: %+v", e.Skip)
+	}
+}
+
+func TestStorePutErrors(t *testing.T) {
+	s := &Store{}
+	for _, tc := range []struct {
+		typ string
+		e   Exception
+	}{
+		{TypeSkip, Exception{Project: " ", Reason: "no project"}},
+		{TypeSkip, Exception{Project: "alpha"}},
+		{"ignore_missing", Exception{Project: "alpha", Reason: "yaml key is not a type"}},
+	} {
+		_, err := s.Put(tc.typ, tc.e)
+		if err == nil {
+			t.Errorf("Put(%s, %+v) succeeded, want an error", tc.typ, tc.e)
+		}
+	}
+}
+
+func TestParseStoreErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"unknown type", "ignore_everything:\n- project: alpha\n  reason: x\n", "yaml.Unmarshal"},
+		{"unknown field", "skip:\n- project: alpha\n  reason: x\n  comment: y\n", "yaml.Unmarshal"},
+		{"not yaml", "skip: [", "yaml.Unmarshal"},
+		{"no reason", "skip:\n- project: alpha\n", "skip/alpha: reason is required"},
+		{"upper case", "skip:\n- project: Alpha\n  reason: x\n", "skip/Alpha: project must be"},
+		{"duplicate", "skip:\n- project: alpha\n  reason: x\n- project: alpha\n  reason: y\n", "skip/alpha: duplicate exception"},
+		{"rename without landscape", "rename:\n- project: alpha\n  reason: x\n", "rename/alpha: landscape name is required"},
+	} {
+		_, err := ParseStore("exceptions.yaml", []byte(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: got error %v, want one containing '%s'", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	typ, project, err := ParseID("ignore-repo/Some Project")
+	if err != nil || typ != TypeIgnoreRepo || project != "some project" {
+		t.Errorf("ParseID = %s, %s, %v", typ, project, err)
+	}
+	for _, id := range []string{"skip", "skip/", ""} {
+		_, _, err = ParseID(id)
+		if err == nil {
+			t.Errorf("ParseID(%s) succeeded, want an error", id)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+)
+
+// SendStatusEmail sends a single status email to all recipients using a given transport, delivery errors are returned
+func SendStatusEmail(transport MailTransport, msgs []string, findings []model.Finding, recipients string) error {
+	fmt.Printf("sending email(s) to %s\n", recipients)
+	from := os.Getenv("EMAIL_FROM")
+	if from == "" {
+		hostname, _ := os.Hostname()
+		from = "devstats-landscape-sync@" + hostname + ".io"
+	}
+	to := []string{}
+	for _, recipient := range strings.Split(recipients, ",") {
+		recipient = strings.TrimSpace(recipient)
+		if recipient != "" {
+			to = append(to, recipient)
+		}
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no email recipients specified")
+	}
+	data, err := report.BuildStatusEmail(from, strings.Join(to, ", "), msgs, findings)
+	if err != nil {
+		return err
+	}
+	err = transport.Send(from, to, data)
+	if err != nil {
+		return fmt.Errorf("sending email to %s: %v", strings.Join(to, ", "), err)
+	}
+	fmt.Printf("sent email to %s\n", strings.Join(to, ", "))
+	return nil
+}
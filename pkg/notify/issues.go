@@ -0,0 +1,251 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/google/go-github/v38/github"
+	"golang.org/x/oauth2"
+)
+
+// Markers stored in issue bodies, used to match issues with findings and to detect value changes
+var (
+	issueKeyRE   = regexp.MustCompile(`<!-- check_sync key: ([^ ]+) -->`)
+	issueStateRE = regexp.MustCompile(`<!-- check_sync state: ([^ ]+) -->`)
+	// locsRE matches source locations at the end of a finding message, they move with unrelated upstream edits
+	locsRE = regexp.MustCompile(` \[[^\[\]]+:[0-9]+(, [^\[\]]+:[0-9]+)*\]$`)
+)
+
+// githubIssuesNotifier keeps one tracking issue per finding (or per project) in a given repo:
+// opens issues for new findings, comments when values change and closes issues of resolved findings
+type githubIssuesNotifier struct {
+	client    *github.Client
+	owner     string
+	repo      string
+	label     string
+	byProject bool
+	owners    *Owners
+}
+
+// trackedProblem is what a single tracking issue is about: a finding or all findings of a project
+type trackedProblem struct {
+	key      string
+	title    string
+	findings []model.Finding
+}
+
+// newGithubIssuesNotifier returns GitHub issues notifier configured via environment variables:
+// GITHUB_ISSUES_REPO=owner/repo, GITHUB_TOKEN, GITHUB_API_URL (for GitHub Enterprise or a local stub),
+// GITHUB_ISSUES_MODE=finding|project (default finding), GITHUB_ISSUES_LABEL (default check-sync)
+func newGithubIssuesNotifier(owners *Owners) (*githubIssuesNotifier, error) {
+	ary := strings.Split(os.Getenv("GITHUB_ISSUES_REPO"), "/")
+	if len(ary) != 2 || ary[0] == "" || ary[1] == "" {
+		return nil, fmt.Errorf("github notifier requires GITHUB_ISSUES_REPO=owner/repo")
+	}
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("GITHUB_ISSUES_MODE")))
+	if mode != "" && mode != "finding" && mode != "project" {
+		return nil, fmt.Errorf("unknown GITHUB_ISSUES_MODE '%s', allowed: finding, project", mode)
+	}
+	label := os.Getenv("GITHUB_ISSUES_LABEL")
+	if label == "" {
+		label = "check-sync"
+	}
+	client := github.NewClient(nil)
+	token := os.Getenv("GITHUB_TOKEN")
+	if token != "" {
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		client = github.NewClient(oauth2.NewClient(context.Background(), ts))
+	}
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL != "" {
+		if !strings.HasSuffix(apiURL, "/") {
+			apiURL += "/"
+		}
+		baseURL, err := url.Parse(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_API_URL '%s': %v", apiURL, err)
+		}
+		client.BaseURL = baseURL
+	}
+	return &githubIssuesNotifier{client: client, owner: ary[0], repo: ary[1], label: label, byProject: mode == "project", owners: owners}, nil
+}
+
+func (n *githubIssuesNotifier) Name() string {
+	return "github"
+}
+
+// problems groups findings into tracked problems, depending on mode
+func (n *githubIssuesNotifier) problems(findings []model.Finding) map[string]*trackedProblem {
+	problems := make(map[string]*trackedProblem)
+	for _, f := range findings {
+		key := f.Fingerprint
+		title := fmt.Sprintf("[check_sync] %s: %s %s", model.CategoryTitles[f.Category], f.Project, f.Field)
+		if n.byProject {
+			key = model.Fingerprint("project", f.Project, "")
+			title = fmt.Sprintf("[check_sync] %s: sync problems", f.Project)
+			if f.Project == "" {
+				title = "[check_sync] global sync problems"
+			}
+		}
+		problem, ok := problems[key]
+		if !ok {
+			problem = &trackedProblem{key: key, title: title}
+			problems[key] = problem
+		}
+		problem.findings = append(problem.findings, f)
+	}
+	return problems
+}
+
+// state returns a hash of all findings without source locations, it changes when any of compared values changes
+func (p *trackedProblem) state() string {
+	msgs := []string{}
+	for _, f := range p.findings {
+		values := locsRE.ReplaceAllString(strings.TrimSpace(f.Message), "")
+		msgs = append(msgs, f.Kind+"\x00"+f.Project+"\x00"+f.Field+"\x00"+values)
+	}
+	sort.Strings(msgs)
+	hash := sha1.Sum([]byte(strings.Join(msgs, "\n")))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+// details returns markdown list of finding messages
+func (p *trackedProblem) details() string {
+	lines := []string{}
+	for _, f := range p.findings {
+		lines = append(lines, "- `"+f.Message+"`")
+		if f.Blame != nil {
+			lines = append(lines, "  "+f.Blame.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// body returns issue body, it mentions given owners' GitHub handles
+func (p *trackedProblem) body(handles []string) string {
+	cc := ""
+	if len(handles) > 0 {
+		cc = "\n\ncc @" + strings.Join(handles, " @")
+	}
+	return fmt.Sprintf(
+		"DevStats <=> landscape sync check reports:\n\n%s%s\n\n<!-- check_sync key: %s -->\n<!-- check_sync state: %s -->\n",
+		p.details(),
+		cc,
+		p.key,
+		p.state(),
+	)
+}
+
+// openIssues returns all open issues with notifier's label, keyed by the key stored in their body
+func (n *githubIssuesNotifier) openIssues(ctx context.Context) (map[string]*github.Issue, error) {
+	issues := make(map[string]*github.Issue)
+	opts := &github.IssueListByRepoOptions{State: "open", Labels: []string{n.label}, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, response, err := n.client.Issues.ListByRepo(ctx, n.owner, n.repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if issue.IsPullRequest() {
+				continue
+			}
+			m := issueKeyRE.FindStringSubmatch(issue.GetBody())
+			if m != nil {
+				issues[m[1]] = issue
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+	return issues, nil
+}
+
+func (n *githubIssuesNotifier) Notify(r *model.Report) error {
+	// Findings dropped by severity filter are still reported, so their issues are not resolved
+	all := r.Findings
+	if r.Unfiltered != nil {
+		all = r.Unfiltered
+	}
+	for _, f := range all {
+		// No comparison was made, do not close issues of findings that simply were not checked
+		if f.Kind == model.KindInput {
+			fmt.Printf("github notifier: skipping, input errors present\n")
+			return nil
+		}
+	}
+	ctx := context.Background()
+	issues, err := n.openIssues(ctx)
+	if err != nil {
+		return err
+	}
+	problems := n.problems(r.Findings)
+	keys := []string{}
+	for key := range problems {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	created, updated, closed := 0, 0, 0
+	for _, key := range keys {
+		problem := problems[key]
+		body := problem.body(n.owners.GithubHandles(problem.findings, r.Aliases))
+		issue, ok := issues[key]
+		if !ok {
+			_, _, err = n.client.Issues.Create(ctx, n.owner, n.repo, &github.IssueRequest{Title: &problem.title, Body: &body, Labels: &[]string{n.label}})
+			if err != nil {
+				return err
+			}
+			created++
+			continue
+		}
+		m := issueStateRE.FindStringSubmatch(issue.GetBody())
+		if m != nil && m[1] == problem.state() {
+			continue
+		}
+		comment := "Values changed, now reported:\n\n" + problem.details()
+		_, _, err = n.client.Issues.CreateComment(ctx, n.owner, n.repo, issue.GetNumber(), &github.IssueComment{Body: &comment})
+		if err != nil {
+			return err
+		}
+		_, _, err = n.client.Issues.Edit(ctx, n.owner, n.repo, issue.GetNumber(), &github.IssueRequest{Body: &body})
+		if err != nil {
+			return err
+		}
+		updated++
+	}
+	reported := n.problems(all)
+	keys = []string{}
+	for key := range issues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, ok := reported[key]
+		if ok {
+			continue
+		}
+		issue := issues[key]
+		comment := "No longer reported by the sync check, closing."
+		_, _, err = n.client.Issues.CreateComment(ctx, n.owner, n.repo, issue.GetNumber(), &github.IssueComment{Body: &comment})
+		if err != nil {
+			return err
+		}
+		state := "closed"
+		_, _, err = n.client.Issues.Edit(ctx, n.owner, n.repo, issue.GetNumber(), &github.IssueRequest{State: &state})
+		if err != nil {
+			return err
+		}
+		closed++
+	}
+	fmt.Printf("github issues in %s/%s: %d opened, %d updated, %d closed\n", n.owner, n.repo, created, updated, closed)
+	return nil
+}
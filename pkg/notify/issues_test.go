@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// stubIssue is an issue kept by githubStub
+type stubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+}
+
+// githubStub is a stub of GitHub issues API of a single repo, it records all changes made
+type githubStub struct {
+	mu      sync.Mutex
+	issues  map[int]*stubIssue
+	actions []string
+}
+
+func newGithubStub(t *testing.T) (*githubStub, *httptest.Server) {
+	stub := &githubStub{issues: make(map[int]*stubIssue)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/repos/cncf/sync/issues")
+		var req struct {
+			Title *string `json:"title"`
+			Body  *string `json:"body"`
+			State *string `json:"state"`
+		}
+		if r.Method != http.MethodGet {
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+			}
+		}
+		parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		number, _ := strconv.Atoi(parts[0])
+		issue := stub.issues[number]
+		switch {
+		case r.Method == http.MethodGet && path == "":
+			if r.URL.Query().Get("state") != "open" || r.URL.Query().Get("labels") != "check-sync" {
+				t.Errorf("unexpected issues query: %s", r.URL.RawQuery)
+			}
+			open := []*stubIssue{}
+			for _, n := range stub.numbers() {
+				if stub.issues[n].State == "open" {
+					open = append(open, stub.issues[n])
+				}
+			}
+			_ = json.NewEncoder(w).Encode(open)
+			return
+		case r.Method == http.MethodPost && path == "":
+			issue = &stubIssue{Number: len(stub.issues) + 1, Title: *req.Title, Body: *req.Body, State: "open"}
+			stub.issues[issue.Number] = issue
+			stub.actions = append(stub.actions, "open "+issue.Title)
+		case issue == nil:
+			http.NotFound(w, r)
+			return
+		case r.Method == http.MethodPost && len(parts) == 2 && parts[1] == "comments":
+			stub.actions = append(stub.actions, "comment "+issue.Title)
+			_, _ = w.Write([]byte("{}"))
+			return
+		case r.Method == http.MethodPatch && len(parts) == 1:
+			if req.Body != nil {
+				issue.Body = *req.Body
+				stub.actions = append(stub.actions, "edit "+issue.Title)
+			}
+			if req.State != nil {
+				issue.State = *req.State
+				stub.actions = append(stub.actions, *req.State+" "+issue.Title)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(issue)
+	}))
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+// numbers returns issue numbers in ascending order, mu must be held
+func (s *githubStub) numbers() []int {
+	numbers := []int{}
+	for n := range s.issues {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// takeActions returns and forgets changes made so far
+func (s *githubStub) takeActions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := s.actions
+	s.actions = nil
+	return actions
+}
+
+// finding returns a finding with fingerprint set like comparisons do
+func finding(kind, severity, project, field, message string) model.Finding {
+	return model.Finding{
+		Kind:        kind,
+		Category:    model.KindCategories[kind],
+		Severity:    severity,
+		Project:     project,
+		Field:       field,
+		Message:     message,
+		Fingerprint: model.Fingerprint(kind, project, field),
+	}
+}
+
+func TestGithubIssuesLifecycle(t *testing.T) {
+	stub, server := newGithubStub(t)
+	t.Setenv("GITHUB_ISSUES_REPO", "cncf/sync")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_ISSUES_MODE", "")
+	t.Setenv("GITHUB_ISSUES_LABEL", "")
+	n, err := newGithubIssuesNotifier(&Owners{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Github notifier only gets errors
+	policy := &model.Policy{Kinds: map[string]string{}, Notifiers: map[string]string{"github": model.SeverityError}}
+	run := func(findings ...model.Finding) []string {
+		t.Helper()
+		err := Run([]Notifier{n}, &model.Report{Findings: findings}, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stub.takeActions()
+	}
+	alpha := finding(model.KindJoin, model.SeverityError, "alpha", "join", "alpha join 2020-01-01 <=> 2020-01-02 [landscape.yml:13, projects.yaml:11]\n")
+	beta := finding(model.KindRepo, model.SeverityError, "beta", "repo", "beta repo a/b <=> a/c [landscape.yml:16]\n")
+	gamma := finding(model.KindStatus, model.SeverityError, "gamma", "status", "gamma status sandbox <=> incubating")
+	warning := finding(model.KindStatus, model.SeverityWarning, "delta", "status", "delta status sandbox <=> incubating")
+	// Issues are changed in order of their keys (finding fingerprints): beta, gamma, alpha
+	if !(beta.Fingerprint < gamma.Fingerprint && gamma.Fingerprint < alpha.Fingerprint) {
+		t.Fatalf("unexpected fingerprints order: %s %s %s", beta.Fingerprint, gamma.Fingerprint, alpha.Fingerprint)
+	}
+	const (
+		alphaTitle = "[check_sync] Join dates: alpha join"
+		betaTitle  = "[check_sync] Main repositories: beta repo"
+		gammaTitle = "[check_sync] Maturity levels: gamma status"
+	)
+	for _, step := range []struct {
+		name     string
+		findings []model.Finding
+		want     []string
+	}{
+		{name: "open", findings: []model.Finding{alpha, beta, warning}, want: []string{"open " + betaTitle, "open " + alphaTitle}},
+		{name: "unchanged", findings: []model.Finding{alpha, beta, warning}},
+		// Upstream edits move lines, compared values stay the same
+		{name: "moved", findings: []model.Finding{
+			finding(model.KindJoin, model.SeverityError, "alpha", "join", "alpha join 2020-01-01 <=> 2020-01-02 [landscape.yml:15, projects.yaml:11]\n"),
+			finding(model.KindRepo, model.SeverityError, "beta", "repo", "beta repo a/b <=> a/c [landscape.yml:18]\n"),
+		}},
+		{name: "changed", findings: []model.Finding{
+			finding(model.KindJoin, model.SeverityError, "alpha", "join", "alpha join 2020-01-01 <=> 2020-01-03 [landscape.yml:13, projects.yaml:11]\n"), beta, gamma,
+		}, want: []string{"open " + gammaTitle, "comment " + alphaTitle, "edit " + alphaTitle}},
+		// beta is still reported, only as a warning the github notifier does not get
+		{name: "filtered is not resolved", findings: []model.Finding{
+			finding(model.KindRepo, model.SeverityWarning, "beta", "repo", "beta repo a/b <=> a/c [landscape.yml:16]\n"), gamma,
+		}, want: []string{"comment " + alphaTitle, "closed " + alphaTitle}},
+		{name: "resolved", want: []string{"comment " + betaTitle, "closed " + betaTitle, "comment " + gammaTitle, "closed " + gammaTitle}},
+		{name: "input errors", findings: []model.Finding{finding(model.KindInput, model.SeverityError, "", "", "error: reading")}},
+	} {
+		got := run(step.findings...)
+		if strings.Join(got, "\n") != strings.Join(step.want, "\n") {
+			t.Errorf("%s: got actions %q, want %q", step.name, got, step.want)
+		}
+	}
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	for _, issue := range stub.issues {
+		if strings.Contains(issue.Body, "<!-- check_sync key: "+alpha.Fingerprint+" -->") && !strings.Contains(issue.Body, "2020-01-03") {
+			t.Errorf("alpha issue body not updated:\n%s", issue.Body)
+		}
+	}
+}
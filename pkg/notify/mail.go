@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+)
+
+// MailTransport delivers a single message to all given recipients
+type MailTransport interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// sendmailTransport pipes the message into a local sendmail binary
+type sendmailTransport struct {
+	path string
+}
+
+// smtpTransport talks SMTP directly to a given server
+// startTLS can be: "auto" - use STARTTLS when server supports it, "require" - fail when it is not supported, "off" - never use it
+type smtpTransport struct {
+	addr     string
+	user     string
+	password string
+	startTLS string
+	insecure bool
+}
+
+// MailConfig holds mail transport settings
+// StartTLS is auto (use STARTTLS when server supports it), require or off
+type MailConfig struct {
+	Transport    string
+	SendmailPath string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	StartTLS     string
+	Insecure     bool
+}
+
+// Mail transports and STARTTLS modes
+var (
+	MailTransports = []string{"sendmail", "smtp"}
+	StartTLSModes  = []string{"auto", "require", "off"}
+)
+
+// MailConfigFromEnv returns mail transport settings from MAIL_TRANSPORT=sendmail|smtp, SENDMAIL_PATH, SMTP_HOST,
+// SMTP_PORT, SMTP_USER, SMTP_PASSWORD, SMTP_STARTTLS=auto|require|off and SMTP_INSECURE_SKIP_VERIFY=1
+func MailConfigFromEnv() MailConfig {
+	cfg := MailConfig{
+		Transport:    strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_TRANSPORT"))),
+		SendmailPath: os.Getenv("SENDMAIL_PATH"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUser:     os.Getenv("SMTP_USER"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		StartTLS:     strings.ToLower(strings.TrimSpace(os.Getenv("SMTP_STARTTLS"))),
+		Insecure:     os.Getenv("SMTP_INSECURE_SKIP_VERIFY") != "",
+	}
+	if cfg.Transport == "" {
+		cfg.Transport = "sendmail"
+	}
+	if cfg.SendmailPath == "" {
+		cfg.SendmailPath = "sendmail"
+	}
+	if cfg.SMTPHost == "" {
+		cfg.SMTPHost = "localhost"
+	}
+	if cfg.SMTPPort == "" {
+		cfg.SMTPPort = "25"
+	}
+	if cfg.StartTLS == "" {
+		cfg.StartTLS = "auto"
+	}
+	return cfg
+}
+
+// NewMailTransport returns mail transport with given settings
+func NewMailTransport(cfg MailConfig) (MailTransport, error) {
+	switch cfg.Transport {
+	case "sendmail":
+		return &sendmailTransport{path: cfg.SendmailPath}, nil
+	case "smtp":
+		if cfg.StartTLS != "auto" && cfg.StartTLS != "require" && cfg.StartTLS != "off" {
+			return nil, fmt.Errorf("unknown STARTTLS mode '%s', allowed: %s", cfg.StartTLS, strings.Join(StartTLSModes, ", "))
+		}
+		return &smtpTransport{
+			addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+			user:     cfg.SMTPUser,
+			password: cfg.SMTPPassword,
+			startTLS: cfg.StartTLS,
+			insecure: cfg.Insecure,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown mail transport '%s', allowed: %s", cfg.Transport, strings.Join(MailTransports, ", "))
+}
+
+func (t *sendmailTransport) Send(from string, to []string, msg []byte) error {
+	res, err := loader.ExecCommandWithStdin(append([]string{t.path, "-i"}, to...), bytes.NewBuffer(msg))
+	if err != nil {
+		return fmt.Errorf("%s: %v: %s", t.path, err, res)
+	}
+	return nil
+}
+
+func (t *smtpTransport) Send(from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(t.addr)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.Dial(t.addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	if t.startTLS != "off" {
+		ok, _ := client.Extension("STARTTLS")
+		if ok {
+			err = client.StartTLS(&tls.Config{ServerName: host, InsecureSkipVerify: t.insecure})
+			if err != nil {
+				return err
+			}
+		} else if t.startTLS == "require" {
+			return fmt.Errorf("%s: server does not support STARTTLS", t.addr)
+		}
+	}
+	if t.user != "" {
+		ok, _ := client.Extension("AUTH")
+		if !ok {
+			return fmt.Errorf("%s: server does not support AUTH", t.addr)
+		}
+		err = client.Auth(smtp.PlainAuth("", t.user, t.password, host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(from)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return fmt.Errorf("RCPT TO %s: %v", recipient, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
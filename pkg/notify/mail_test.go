@@ -0,0 +1,214 @@
+package notify
+
+import (
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// fakeSMTP is a minimal SMTP server accepting sessions on a local listener and recording them
+type fakeSMTP struct {
+	addr       string
+	extensions []string
+	// rejectRcpt is a recipient refused with 550
+	rejectRcpt string
+	mu         sync.Mutex
+	commands   []string
+	auth       string
+	data       []string
+}
+
+// newFakeSMTP starts a fake SMTP server advertising given EHLO extensions, it is stopped when the test ends
+func newFakeSMTP(t *testing.T, extensions ...string) *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	s := &fakeSMTP{addr: l.Addr().String(), extensions: extensions}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	c := textproto.NewConn(conn)
+	_ = c.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		s.mu.Lock()
+		s.commands = append(s.commands, cmd)
+		s.mu.Unlock()
+		switch cmd {
+		case "EHLO":
+			ext := append([]string{"fake"}, s.extensions...)
+			for i, e := range ext {
+				sep := "-"
+				if i == len(ext)-1 {
+					sep = " "
+				}
+				_ = c.PrintfLine("250%s%s", sep, e)
+			}
+		case "AUTH":
+			s.mu.Lock()
+			fields := strings.Fields(line)
+			if len(fields) == 3 {
+				auth, _ := base64.StdEncoding.DecodeString(fields[2])
+				s.auth = string(auth)
+			}
+			s.mu.Unlock()
+			_ = c.PrintfLine("235 authenticated")
+		case "MAIL":
+			_ = c.PrintfLine("250 sender ok")
+		case "RCPT":
+			if s.rejectRcpt != "" && strings.Contains(line, "<"+s.rejectRcpt+">") {
+				_ = c.PrintfLine("550 no such user")
+				continue
+			}
+			_ = c.PrintfLine("250 recipient ok")
+		case "DATA":
+			_ = c.PrintfLine("354 go ahead")
+			lines, err := c.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = lines
+			s.mu.Unlock()
+			_ = c.PrintfLine("250 queued")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("502 %s not implemented", cmd)
+		}
+	}
+}
+
+// session returns recorded commands (space separated) and message lines
+func (s *fakeSMTP) session() (string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.commands, " "), s.data
+}
+
+func TestSMTPTransport(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		extensions []string
+		transport  smtpTransport
+		commands   string
+		auth       string
+		err        string
+	}{
+		{name: "plain", transport: smtpTransport{startTLS: "auto"}, commands: "EHLO MAIL RCPT RCPT DATA QUIT"},
+		{name: "starttls off", extensions: []string{"STARTTLS"}, transport: smtpTransport{startTLS: "off"}, commands: "EHLO MAIL RCPT RCPT DATA QUIT"},
+		{name: "starttls required", transport: smtpTransport{startTLS: "require"}, commands: "EHLO", err: "server does not support STARTTLS"},
+		{
+			name:       "auth",
+			extensions: []string{"AUTH PLAIN"},
+			transport:  smtpTransport{user: "user", password: "secret"},
+			commands:   "EHLO AUTH MAIL RCPT RCPT DATA QUIT",
+			auth:       "\x00user\x00secret",
+		},
+		{name: "auth not supported", transport: smtpTransport{user: "user", password: "secret"}, commands: "EHLO", err: "server does not support AUTH"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeSMTP(t, tc.extensions...)
+			transport := tc.transport
+			transport.addr = server.addr
+			err := transport.Send("sync@example.com", []string{"a@example.com", "b@example.com"}, []byte("Subject: test\r\n\r\nhello\r\n"))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v, want '%s'", err, tc.err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			commands, data := server.session()
+			if !strings.HasPrefix(commands, tc.commands) {
+				t.Errorf("got commands '%s', want '%s'", commands, tc.commands)
+			}
+			server.mu.Lock()
+			auth := server.auth
+			server.mu.Unlock()
+			if auth != tc.auth {
+				t.Errorf("got AUTH PLAIN '%q', want '%q'", auth, tc.auth)
+			}
+			if tc.err == "" && strings.Join(data, "\n") != "Subject: test\n\nhello" {
+				t.Errorf("got message %q", data)
+			}
+		})
+	}
+}
+
+// TestEmailNotifierSMTPError makes sure a rejected recipient fails the notifier run, so check_sync exits with an error
+func TestEmailNotifierSMTPError(t *testing.T) {
+	server := newFakeSMTP(t)
+	server.rejectRcpt = "nobody@example.com"
+	host, port, _ := net.SplitHostPort(server.addr)
+	t.Setenv("EMAIL_FROM", "sync@example.com")
+	transport, err := NewMailTransport(MailConfig{Transport: "smtp", SMTPHost: host, SMTPPort: port, StartTLS: "auto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &model.Report{
+		Msgs:     []string{"missing\n"},
+		Findings: []model.Finding{{Kind: model.KindMissingDevstats, Severity: model.SeverityError, Project: "alpha", Message: "missing"}},
+	}
+	n := &emailNotifier{recipients: "admin@example.com, nobody@example.com", owners: &Owners{}, transport: transport}
+	err = Run([]Notifier{n}, r, model.DefaultPolicy())
+	if err == nil || !strings.Contains(err.Error(), "RCPT TO nobody@example.com") {
+		t.Fatalf("got error %v, want rejected recipient", err)
+	}
+	commands, _ := server.session()
+	if commands != "EHLO MAIL RCPT RCPT" {
+		t.Errorf("got commands '%s'", commands)
+	}
+}
+
+func TestMailConfig(t *testing.T) {
+	for _, env := range []string{"MAIL_TRANSPORT", "SENDMAIL_PATH", "SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASSWORD", "SMTP_STARTTLS", "SMTP_INSECURE_SKIP_VERIFY"} {
+		t.Setenv(env, "")
+	}
+	cfg := MailConfigFromEnv()
+	want := MailConfig{Transport: "sendmail", SendmailPath: "sendmail", SMTPHost: "localhost", SMTPPort: "25", StartTLS: "auto"}
+	if cfg != want {
+		t.Errorf("got defaults %+v, want %+v", cfg, want)
+	}
+	t.Setenv("MAIL_TRANSPORT", " SMTP ")
+	t.Setenv("SMTP_HOST", "mail.example.com")
+	t.Setenv("SMTP_STARTTLS", "Require")
+	t.Setenv("SMTP_INSECURE_SKIP_VERIFY", "1")
+	transport, err := NewMailTransport(MailConfigFromEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	smtp, ok := transport.(*smtpTransport)
+	if !ok || smtp.addr != "mail.example.com:25" || smtp.startTLS != "require" || !smtp.insecure {
+		t.Errorf("got transport %+v", transport)
+	}
+	for _, cfg := range []MailConfig{{Transport: "pigeon"}, {Transport: "smtp", StartTLS: "maybe"}} {
+		_, err = NewMailTransport(cfg)
+		if err == nil {
+			t.Errorf("%+v: got no error", cfg)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package notify delivers sync reports: emails (to admins and owners), Slack and generic webhooks
+// and GitHub tracking issues. Notifiers and mail transports are given by Config (defaults come from environment
+// variables), webhooks and GitHub issues are configured via environment variables.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// Notifier delivers a sync report to some destination
+// Notifiers are called after every run, also when there are no findings (so they can resolve previous ones)
+type Notifier interface {
+	Name() string
+	Notify(r *model.Report) error
+}
+
+// emailNotifier sends the full report as an email to admins and a tailored report to each owner
+type emailNotifier struct {
+	recipients string
+	owners     *Owners
+	transport  MailTransport
+}
+
+func (n *emailNotifier) Name() string {
+	return "email"
+}
+
+func (n *emailNotifier) Notify(r *model.Report) error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+	err := SendStatusEmail(n.transport, r.Msgs, r.Findings, n.recipients)
+	routes := n.owners.RouteEmails(r.Findings, r.Aliases)
+	emails := []string{}
+	for email := range routes {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	for _, email := range emails {
+		msgs := []string{}
+		for _, f := range routes[email] {
+			msgs = append(msgs, f.Message+"\n")
+		}
+		ownerErr := SendStatusEmail(n.transport, msgs, routes[email], email)
+		if ownerErr != nil && err == nil {
+			err = ownerErr
+		}
+	}
+	return err
+}
+
+// Config holds notifier settings: Notifiers is a comma separated list of email, slack, webhook and github
+type Config struct {
+	Notifiers string
+	Mail      MailConfig
+}
+
+// ConfigFromEnv returns notifier settings from NOTIFIERS (default email) and mail transport environment variables
+func ConfigFromEnv() Config {
+	cfg := Config{Notifiers: os.Getenv("NOTIFIERS"), Mail: MailConfigFromEnv()}
+	if cfg.Notifiers == "" {
+		cfg.Notifiers = "email"
+	}
+	return cfg
+}
+
+// New returns given notifiers, skipEmail disables email notifier, other settings come from environment variables:
+// SLACK_WEBHOOK_URL, WEBHOOK_URL, NOTIFY_TOP_N (how many new findings webhooks include, default 10)
+func New(cfg Config, owners *Owners, skipEmail bool) ([]Notifier, error) {
+	topN := 10
+	topNStr := os.Getenv("NOTIFY_TOP_N")
+	if topNStr != "" {
+		n, err := strconv.Atoi(topNStr)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid NOTIFY_TOP_N value '%s'", topNStr)
+		}
+		topN = n
+	}
+	notifiers := []Notifier{}
+	for _, name := range strings.Split(cfg.Notifiers, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "email":
+			if skipEmail {
+				continue
+			}
+			transport, err := NewMailTransport(cfg.Mail)
+			if err != nil {
+				return nil, err
+			}
+			recipients := owners.AdminRecipients()
+			if strings.TrimSpace(recipients) == "" {
+				return nil, fmt.Errorf("email notifier has no global digest recipients, set -email-to (EMAIL_TO) or admins in owners.yaml")
+			}
+			notifiers = append(notifiers, &emailNotifier{recipients: recipients, owners: owners, transport: transport})
+		case "slack":
+			url := os.Getenv("SLACK_WEBHOOK_URL")
+			if url == "" {
+				return nil, fmt.Errorf("slack notifier requires SLACK_WEBHOOK_URL")
+			}
+			notifiers = append(notifiers, &slackNotifier{url: url, topN: topN})
+		case "webhook":
+			url := os.Getenv("WEBHOOK_URL")
+			if url == "" {
+				return nil, fmt.Errorf("webhook notifier requires WEBHOOK_URL")
+			}
+			notifiers = append(notifiers, &webhookNotifier{url: url, topN: topN})
+		case "github":
+			n, err := newGithubIssuesNotifier(owners)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, n)
+		default:
+			return nil, fmt.Errorf("unknown notifier '%s', allowed: %s", name, strings.Join(model.Notifiers, ", "))
+		}
+	}
+	return notifiers, nil
+}
+
+// filterReport returns a copy of a report with only findings at least as severe as min,
+// output lines of dropped findings are dropped too, all findings are kept in Unfiltered
+func filterReport(r *model.Report, min string) *model.Report {
+	filtered := *r
+	if filtered.Unfiltered == nil {
+		filtered.Unfiltered = r.Findings
+	}
+	filtered.Findings = model.FilterSeverity(r.Findings, min)
+	filtered.New = model.FilterSeverity(r.New, min)
+	if len(filtered.Findings) == len(r.Findings) {
+		return &filtered
+	}
+	dropped := make(map[string]struct{})
+	for _, f := range r.Findings {
+		if !model.AtLeast(f.Severity, min) {
+			dropped[f.Message] = struct{}{}
+		}
+	}
+	filtered.Msgs = []string{}
+	for _, msg := range r.Msgs {
+		_, drop := dropped[strings.TrimSpace(msg)]
+		if !drop {
+			filtered.Msgs = append(filtered.Msgs, msg)
+		}
+	}
+	return &filtered
+}
+
+// Run calls all notifiers, all of them are called even if some fail, first error is returned
+// Each notifier only gets findings at least as severe as its minimum severity from a given policy
+func Run(notifiers []Notifier, r *model.Report, policy *model.Policy) (err error) {
+	for _, n := range notifiers {
+		nErr := n.Notify(filterReport(r, policy.NotifierSeverity(n.Name())))
+		if nErr != nil {
+			fmt.Printf("error: %s notifier: %v\n", n.Name(), nErr)
+			if err == nil {
+				err = fmt.Errorf("%s notifier: %v", n.Name(), nErr)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OwnerInfo lists who should be notified about some findings
+type OwnerInfo struct {
+	Emails []string `yaml:"emails"`
+	GitHub []string `yaml:"github"`
+}
+
+// Owners is the ownership map read from owners.yaml
+// Admins get the global digest, projects are keyed by DevStats or landscape name, categories by finding category
+// EmailTo overrides admins when set
+type Owners struct {
+	EmailTo    string               `yaml:"-"`
+	Admins     []string             `yaml:"admins"`
+	Projects   map[string]OwnerInfo `yaml:"projects"`
+	Categories map[string]OwnerInfo `yaml:"categories"`
+}
+
+// LoadOwners reads the ownership map from a given url|path, default is owners.yaml which can be missing:
+// then nothing is routed to owners and there are no admins, so the global digest needs EmailTo
+func LoadOwners(path string) (*Owners, error) {
+	optional := path == ""
+	if optional {
+		path = "owners.yaml"
+	}
+	owners := &Owners{}
+	if optional {
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			fmt.Printf("no ownership map in '%s', global digest only goes to -email-to (EMAIL_TO) recipients\n", path)
+			return owners, nil
+		}
+	}
+	data, err := loader.ReadPathOrURL(path)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, owners)
+	if err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal '%s' -> %+v", path, err)
+	}
+	projects := make(map[string]OwnerInfo)
+	for project, info := range owners.Projects {
+		projects[strings.ToLower(strings.TrimSpace(project))] = info
+	}
+	owners.Projects = projects
+	for category := range owners.Categories {
+		_, ok := model.CategoryTitles[category]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown category '%s'", path, category)
+		}
+	}
+	return owners, nil
+}
+
+// AdminRecipients returns global digest recipients: EmailTo or admins from the ownership map
+func (o *Owners) AdminRecipients() string {
+	recipients := o.EmailTo
+	if recipients == "" {
+		recipients = strings.Join(o.Admins, ",")
+	}
+	return recipients
+}
+
+// OwnersOf returns owners of a given finding: its project's owners and its category's owners
+// aliases maps DevStats names to landscape names and vice versa, so both can be used in the ownership map
+func (o *Owners) OwnersOf(f model.Finding, aliases map[string]string) []OwnerInfo {
+	owners := []OwnerInfo{}
+	if f.Project != "" {
+		info, ok := o.Projects[f.Project]
+		if !ok {
+			info, ok = o.Projects[aliases[f.Project]]
+		}
+		if ok {
+			owners = append(owners, info)
+		}
+	}
+	info, ok := o.Categories[f.Category]
+	if ok {
+		owners = append(owners, info)
+	}
+	return owners
+}
+
+// RouteEmails returns findings that each owner's email should get
+func (o *Owners) RouteEmails(findings []model.Finding, aliases map[string]string) map[string][]model.Finding {
+	routes := make(map[string][]model.Finding)
+	for _, f := range findings {
+		seen := make(map[string]struct{})
+		for _, info := range o.OwnersOf(f, aliases) {
+			for _, email := range info.Emails {
+				email = strings.TrimSpace(email)
+				_, dup := seen[email]
+				if email == "" || dup {
+					continue
+				}
+				seen[email] = struct{}{}
+				routes[email] = append(routes[email], f)
+			}
+		}
+	}
+	return routes
+}
+
+// GithubHandles returns sorted GitHub handles of all owners of given findings
+func (o *Owners) GithubHandles(findings []model.Finding, aliases map[string]string) []string {
+	handles := make(map[string]struct{})
+	for _, f := range findings {
+		for _, info := range o.OwnersOf(f, aliases) {
+			for _, handle := range info.GitHub {
+				handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+				if handle != "" {
+					handles[handle] = struct{}{}
+				}
+			}
+		}
+	}
+	ary := []string{}
+	for handle := range handles {
+		ary = append(ary, handle)
+	}
+	sort.Strings(ary)
+	return ary
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// inTempDir runs the rest of a test in a new temporary working directory
+func inTempDir(t *testing.T) string {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	return dir
+}
+
+// TestLoadOwnersMissing makes sure a missing default owners.yaml routes nothing and the digest needs explicit recipients
+func TestLoadOwnersMissing(t *testing.T) {
+	inTempDir(t)
+	t.Setenv("NOTIFY_TOP_N", "")
+	owners, err := LoadOwners("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners.AdminRecipients() != "" {
+		t.Errorf("got admin recipients '%s' without an ownership map", owners.AdminRecipients())
+	}
+	f := model.Finding{Kind: model.KindRepo, Category: model.CatRepo, Project: "alpha"}
+	if len(owners.RouteEmails([]model.Finding{f}, nil)) != 0 || len(owners.GithubHandles([]model.Finding{f}, nil)) != 0 {
+		t.Errorf("findings routed without an ownership map")
+	}
+	cfg := Config{Notifiers: "email", Mail: MailConfig{Transport: "sendmail", SendmailPath: "sendmail"}}
+	_, err = New(cfg, owners, false)
+	if err == nil || !strings.Contains(err.Error(), "no global digest recipients") {
+		t.Errorf("got error %v, want no recipients", err)
+	}
+	notifiers, err := New(cfg, owners, true)
+	if err != nil || len(notifiers) != 0 {
+		t.Errorf("got notifiers %+v, error %v with email skipped", notifiers, err)
+	}
+	owners.EmailTo = "digest@example.com"
+	notifiers, err = New(cfg, owners, false)
+	if err != nil || len(notifiers) != 1 || notifiers[0].(*emailNotifier).recipients != "digest@example.com" {
+		t.Errorf("got notifiers %+v, error %v", notifiers, err)
+	}
+	// Explicitly configured ownership map must exist
+	_, err = LoadOwners("owners.yaml")
+	if err == nil {
+		t.Errorf("missing ownership map given explicitly was loaded")
+	}
+}
+
+func TestLoadOwners(t *testing.T) {
+	dir := inTempDir(t)
+	data := `admins: [admin@example.com]
+projects:
+  " Alpha ":
+    emails: [alpha@example.com]
+    github: ["@alpha-dev"]
+categories:
+  repo:
+    emails: [repos@example.com, alpha@example.com]
+    github: [repo-team]
+`
+	err := ioutil.WriteFile(filepath.Join(dir, "owners.yaml"), []byte(data), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owners, err := LoadOwners("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners.AdminRecipients() != "admin@example.com" {
+		t.Errorf("got admin recipients '%s'", owners.AdminRecipients())
+	}
+	repo := model.Finding{Kind: model.KindRepo, Category: model.CatRepo, Project: "alpha landscape"}
+	join := model.Finding{Kind: model.KindJoin, Category: model.CatJoin, Project: "beta"}
+	aliases := map[string]string{"alpha landscape": "alpha"}
+	routes := owners.RouteEmails([]model.Finding{repo, join}, aliases)
+	want := map[string][]model.Finding{"alpha@example.com": {repo}, "repos@example.com": {repo}}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("got routes %+v, want %+v", routes, want)
+	}
+	handles := owners.GithubHandles([]model.Finding{repo, join}, aliases)
+	if !reflect.DeepEqual(handles, []string{"alpha-dev", "repo-team"}) {
+		t.Errorf("got handles %v", handles)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "owners.yaml"), []byte("categories:\n  colors: {}\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadOwners("")
+	if err == nil || !strings.Contains(err.Error(), "unknown category 'colors'") {
+		t.Errorf("got error %v, want unknown category", err)
+	}
+}
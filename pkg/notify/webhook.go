@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+)
+
+var webhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// slackNotifier posts a compact summary to a Slack-compatible incoming webhook (Slack, Mattermost)
+type slackNotifier struct {
+	url  string
+	topN int
+}
+
+// webhookNotifier posts a JSON summary to a generic webhook
+type webhookNotifier struct {
+	url  string
+	topN int
+}
+
+// webhookPayload is the JSON document posted by webhookNotifier
+type webhookPayload struct {
+	Title      string          `json:"title"`
+	Total      int             `json:"total"`
+	New        int             `json:"new"`
+	Counts     map[string]int  `json:"counts"`
+	Severities map[string]int  `json:"severities"`
+	Findings   []model.Finding `json:"findings"`
+}
+
+func (n *slackNotifier) Name() string {
+	return "slack"
+}
+
+func (n *slackNotifier) Notify(r *model.Report) error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+	counts := r.CategoryCounts()
+	lines := []string{fmt.Sprintf("*%s*: %s, %d new", report.Title, model.SeveritySummary(r.Findings), len(r.New))}
+	for _, category := range model.Categories {
+		count, ok := counts[category]
+		if ok {
+			lines = append(lines, fmt.Sprintf("• %s: %d", model.CategoryTitles[category], count))
+		}
+	}
+	top := topFindings(r.New, n.topN)
+	if len(top) > 0 {
+		lines = append(lines, "New findings:")
+		for _, f := range top {
+			lines = append(lines, "• `"+f.Message+"`")
+			if f.Blame != nil {
+				lines = append(lines, "  "+f.Blame.String())
+			}
+		}
+		if len(r.New) > len(top) {
+			lines = append(lines, fmt.Sprintf("… and %d more", len(r.New)-len(top)))
+		}
+	}
+	return postJSON(n.url, map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(r *model.Report) error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+	return postJSON(
+		n.url,
+		webhookPayload{
+			Title:      report.Subject(r.Findings),
+			Total:      len(r.Findings),
+			New:        len(r.New),
+			Counts:     r.CategoryCounts(),
+			Severities: model.SeverityCounts(r.Findings),
+			Findings:   topFindings(r.New, n.topN),
+		},
+	)
+}
+
+// topFindings returns up to n first findings
+func topFindings(findings []model.Finding, n int) []model.Finding {
+	if len(findings) > n {
+		return findings[:n]
+	}
+	return findings
+}
+
+// postJSON posts a given payload as JSON, non 2xx response is an error
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	response, err := webhookClient.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("HTTP %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// testReport returns a report with three findings, two of them new
+func testReport() *model.Report {
+	findings := []model.Finding{
+		{Kind: model.KindMissingDevstats, Category: model.CatMissingDevstats, Severity: model.SeverityError, Project: "alpha", Field: "name", Message: "alpha is missing"},
+		{Kind: model.KindRepo, Category: model.CatRepo, Severity: model.SeverityWarning, Project: "beta", Field: "repo", Message: "beta repo differs"},
+		{Kind: model.KindJoin, Category: model.CatJoin, Severity: model.SeverityError, Project: "gamma", Field: "join", Message: "gamma join date differs"},
+	}
+	return &model.Report{Findings: findings, New: findings[1:]}
+}
+
+// recordServer returns a test server answering with a given status and the last request body it got
+func recordServer(t *testing.T, status int) (*httptest.Server, *[]byte) {
+	body := []byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s request with content type '%s'", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("rejected\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, body := recordServer(t, http.StatusOK)
+	err := (&slackNotifier{url: server.URL, topN: 1}).Notify(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]string
+	err = json.Unmarshal(*body, &payload)
+	if err != nil {
+		t.Fatalf("%v: %s", err, *body)
+	}
+	text := payload["text"]
+	for _, want := range []string{"2 errors, 1 warning, 2 new", "New findings:", "• `beta repo differs`", "… and 1 more"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slack text does not contain '%s':\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "gamma join date differs") {
+		t.Errorf("slack text contains more than top 1 new findings:\n%s", text)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, body := recordServer(t, http.StatusNoContent)
+	err := (&webhookNotifier{url: server.URL, topN: 10}).Notify(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload webhookPayload
+	err = json.Unmarshal(*body, &payload)
+	if err != nil {
+		t.Fatalf("%v: %s", err, *body)
+	}
+	if payload.Total != 3 || payload.New != 2 || len(payload.Findings) != 2 || payload.Findings[0].Project != "beta" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if payload.Severities[model.SeverityError] != 2 || payload.Counts[model.CatMissingDevstats] != 1 || payload.Title == "" {
+		t.Errorf("unexpected payload counts: %+v", payload)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway} {
+		server, _ := recordServer(t, status)
+		for _, n := range []Notifier{&slackNotifier{url: server.URL}, &webhookNotifier{url: server.URL}} {
+			err := n.Notify(testReport())
+			if err == nil || !strings.Contains(err.Error(), "rejected") || !strings.HasPrefix(err.Error(), "HTTP ") {
+				t.Errorf("%s notifier, HTTP %d: got error %v", n.Name(), status, err)
+			}
+		}
+	}
+	// No findings: nothing is posted
+	server, body := recordServer(t, http.StatusInternalServerError)
+	err := (&webhookNotifier{url: server.URL}).Notify(&model.Report{})
+	if err != nil || len(*body) != 0 {
+		t.Errorf("got error %v and body %s for an empty report", err, *body)
+	}
+}
@@ -0,0 +1,179 @@
+// Package report renders sync reports (plain text and HTML email, Prometheus metrics) and stores them
+// (last run's findings state, history database).
+package report
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// Title is the title of all reports
+const Title = "DevStats <=> landscape sync status"
+
+// Subject returns report title with number of findings per severity, like "...: 3 errors, 5 warnings"
+func Subject(findings []model.Finding) string {
+	summary := model.SeveritySummary(findings)
+	if summary == "" {
+		return Title
+	}
+	return Title + ": " + summary
+}
+
+// Template renders an HTML report from Data
+var Template = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <style>
+    table { border-collapse: collapse; }
+    th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; vertical-align: top; }
+  </style>
+</head>
+<body>
+<h2>{{.Title}}</h2>
+{{if .Groups}}
+<table>
+  <tr><th>Category</th><th>Findings</th></tr>
+{{- range .Groups}}
+  <tr><td><a href="#{{.Category}}">{{.Title}}</a></td><td>{{len .Findings}}</td></tr>
+{{- end}}
+  <tr><th>Total</th><th>{{.Total}}</th></tr>
+</table>
+{{range .Groups}}
+<details id="{{.Category}}" open>
+<summary><b>{{.Title}}</b> ({{len .Findings}})</summary>
+<table>
+  <tr><th>Severity</th><th>Project</th><th>Field</th><th>Details</th></tr>
+{{- range .Findings}}
+  <tr><td>{{.Severity}}</td><td>{{.Project}}</td><td>{{.Field}}</td><td>{{.Message}}{{with .Blame}}<br><small>{{.}}</small>{{end}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{end}}
+{{else}}
+<p>No findings.</p>
+{{end}}
+<details>
+<summary>Full output</summary>
+<pre>{{.Log}}</pre>
+</details>
+</body>
+</html>
+`))
+
+// Data is the data passed to Template
+type Data struct {
+	Title  string
+	Groups []model.FindingsGroup
+	Total  int
+	Log    string
+}
+
+// quotedPrintable encodes a given text using quoted-printable encoding
+func quotedPrintable(text string) (string, error) {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	_, err := w.Write([]byte(text))
+	if err != nil {
+		return "", err
+	}
+	err = w.Close()
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// messageID returns a new unique Message-ID header value
+func messageID(hostname string) string {
+	rnd := make([]byte, 8)
+	_, _ = rand.Read(rnd)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(rnd), hostname)
+}
+
+// messageHost returns the domain of a given From address, hostname when it has none
+func messageHost(from string) string {
+	addr, err := mail.ParseAddress(from)
+	if err == nil {
+		i := strings.LastIndex(addr.Address, "@")
+		if i >= 0 && i < len(addr.Address)-1 {
+			return addr.Address[i+1:]
+		}
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "localhost"
+	}
+	return hostname
+}
+
+// BuildStatusEmail returns a multipart/alternative (text/plain + text/html) message
+func BuildStatusEmail(from, recipient string, msgs []string, findings []model.Finding) ([]byte, error) {
+	groups := model.GroupFindings(findings)
+	text := strings.Join(msgs, "")
+	var htmlBody bytes.Buffer
+	err := Template.Execute(&htmlBody, Data{Title: Title, Groups: groups, Total: len(findings), Log: text})
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: text},
+		{contentType: "text/html; charset=utf-8", content: htmlBody.String()},
+	}
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := quotedPrintable(part.content)
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write([]byte(encoded))
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", recipient},
+		{"Subject", mime.QEncoding.Encode("utf-8", Subject(findings))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(messageHost(from))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=\"%s\"", writer.Boundary())},
+	}
+	for _, header := range headers {
+		msg.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
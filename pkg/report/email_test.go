@@ -0,0 +1,62 @@
+package report_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+)
+
+func TestBuildStatusEmail(t *testing.T) {
+	hostname, _ := os.Hostname()
+	findings := []model.Finding{model.NewFinding(model.KindRepo, model.SeverityError, "alpha", "repo", "error: alpha repo <a/b>\n")}
+	for _, tc := range []struct {
+		from, domain string
+	}{
+		{from: "sync@example.com", domain: "example.com"},
+		{from: "DevStats Sync <sync@example.org>", domain: "example.org"},
+		{from: "sync", domain: hostname},
+	} {
+		data, err := report.BuildStatusEmail(tc.from, "admin@example.com", []string{"error: alpha repo <a/b>\n"}, findings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := mail.ReadMessage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.from, err)
+		}
+		if id := msg.Header.Get("Message-ID"); !strings.HasSuffix(id, "@"+tc.domain+">") {
+			t.Errorf("%s: got Message-ID '%s', want domain '%s'", tc.from, id, tc.domain)
+		}
+		if msg.Header.Get("From") != tc.from || !strings.Contains(msg.Header.Get("Subject"), "1 error") {
+			t.Errorf("%s: got headers %v", tc.from, msg.Header)
+		}
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/alternative" {
+			t.Fatalf("%s: got content type %s, %v", tc.from, mediaType, err)
+		}
+		types := []string{}
+		r := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := r.NextPart()
+			if err != nil {
+				break
+			}
+			body, _ := ioutil.ReadAll(part)
+			types = append(types, part.Header.Get("Content-Type"))
+			if !strings.Contains(string(body), "alpha repo") {
+				t.Errorf("%s: %s part does not contain the finding:\n%s", tc.from, part.Header.Get("Content-Type"), body)
+			}
+		}
+		if strings.Join(types, ", ") != "text/plain; charset=utf-8, text/html; charset=utf-8" {
+			t.Errorf("%s: got parts %v", tc.from, types)
+		}
+	}
+}
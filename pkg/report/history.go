@@ -0,0 +1,219 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// History database buckets: runs are keyed by start time, findings by fingerprint
+var (
+	runsBucket     = []byte("runs")
+	findingsBucket = []byte("findings")
+)
+
+// RunRecord is a single run stored in the history database
+type RunRecord struct {
+	Started      time.Time         `json:"started"`
+	Finished     time.Time         `json:"finished"`
+	Error        string            `json:"error,omitempty"`
+	Inputs       []model.FetchStat `json:"inputs"`
+	Counts       map[string]int    `json:"counts"`
+	Fingerprints []string          `json:"fingerprints"`
+}
+
+// FindingRecord holds when a given finding was first and last reported
+type FindingRecord struct {
+	model.Finding
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Runs      int       `json:"runs"`
+}
+
+func openHistory(path string, readOnly bool) (*bolt.DB, error) {
+	return bolt.Open(path, 0644, &bolt.Options{Timeout: 10 * time.Second, ReadOnly: readOnly})
+}
+
+// RecordHistory stores a finished run and updates first/last seen dates of its findings
+func RecordHistory(path string, rep *model.Report) error {
+	db, err := openHistory(path, false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	return db.Update(func(tx *bolt.Tx) error {
+		runs, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		findings, err := tx.CreateBucketIfNotExists(findingsBucket)
+		if err != nil {
+			return err
+		}
+		run := RunRecord{
+			Started:      rep.Started,
+			Finished:     rep.Finished,
+			Error:        rep.Error,
+			Inputs:       rep.Fetches,
+			Counts:       rep.CategoryCounts(),
+			Fingerprints: []string{},
+		}
+		inputErrors := false
+		for _, f := range rep.Findings {
+			run.Fingerprints = append(run.Fingerprints, f.Fingerprint)
+			if f.Kind == model.KindInput {
+				inputErrors = true
+			}
+		}
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		err = runs.Put([]byte(rep.Started.UTC().Format(time.RFC3339Nano)), data)
+		if err != nil {
+			return err
+		}
+		// No comparison was made, so nothing is known about findings
+		if inputErrors {
+			return nil
+		}
+		for _, f := range rep.Findings {
+			rec := FindingRecord{FirstSeen: rep.Started}
+			data := findings.Get([]byte(f.Fingerprint))
+			if data != nil {
+				err = json.Unmarshal(data, &rec)
+				if err != nil {
+					return err
+				}
+			}
+			rec.Finding = f
+			rec.LastSeen = rep.Started
+			rec.Runs++
+			data, err = json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+			err = findings.Put([]byte(f.Fingerprint), data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadHistory returns all stored runs (oldest first) and findings
+func LoadHistory(path string) ([]RunRecord, []FindingRecord, error) {
+	db, err := openHistory(path, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = db.Close() }()
+	runs := []RunRecord{}
+	findings := []FindingRecord{}
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(runsBucket)
+		if bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				var run RunRecord
+				err := json.Unmarshal(v, &run)
+				runs = append(runs, run)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		bucket = tx.Bucket(findingsBucket)
+		if bucket != nil {
+			return bucket.ForEach(func(k, v []byte) error {
+				var rec FindingRecord
+				err := json.Unmarshal(v, &rec)
+				findings = append(findings, rec)
+				return err
+			})
+		}
+		return nil
+	})
+	return runs, findings, err
+}
+
+// PrintHistory prints the trend per category (last run of each day) for that many last days and first/last seen
+// dates of findings, optionally only about a given project or only open ones (reported by the last successful run)
+func PrintHistory(out io.Writer, path string, days int, project string, open bool) error {
+	runs, findings, err := LoadHistory(path)
+	if err != nil {
+		return err
+	}
+	// Last run of each day, skipping runs that failed to read inputs
+	from := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	days2runs := make(map[string]RunRecord)
+	lastOK := time.Time{}
+	for _, run := range runs {
+		if run.Counts[model.CatInput] > 0 {
+			continue
+		}
+		if run.Started.After(lastOK) {
+			lastOK = run.Started
+		}
+		day := run.Started.Format("2006-01-02")
+		if day >= from {
+			days2runs[day] = run
+		}
+	}
+	dates := []string{}
+	for day := range days2runs {
+		dates = append(dates, day)
+	}
+	sort.Strings(dates)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "date\ttotal\t%s\n", strings.Join(model.Categories[1:], "\t"))
+	for _, day := range dates {
+		run := days2runs[day]
+		row := []string{day, fmt.Sprintf("%d", len(run.Fingerprints))}
+		for _, category := range model.Categories[1:] {
+			row = append(row, fmt.Sprintf("%d", run.Counts[category]))
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	fmt.Fprintf(out, "\n")
+	sort.Slice(findings, func(i, j int) bool {
+		if !findings[i].FirstSeen.Equal(findings[j].FirstSeen) {
+			return findings[i].FirstSeen.Before(findings[j].FirstSeen)
+		}
+		return findings[i].Fingerprint < findings[j].Fingerprint
+	})
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "fingerprint\tfirst seen\tlast seen\truns\tstate\tmessage\n")
+	for _, rec := range findings {
+		if project != "" && !strings.EqualFold(rec.Project, project) {
+			continue
+		}
+		state := "resolved"
+		if rec.LastSeen.Equal(lastOK) {
+			state = "open"
+		}
+		if open && state != "open" {
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%d\t%s\t%s\n",
+			rec.Fingerprint,
+			rec.FirstSeen.Format("2006-01-02"),
+			rec.LastSeen.Format("2006-01-02"),
+			rec.Runs,
+			state,
+			rec.Message,
+		)
+	}
+	return w.Flush()
+}
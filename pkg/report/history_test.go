@@ -0,0 +1,113 @@
+package report
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// historyFinding returns a finding with fingerprint set like comparisons do
+func historyFinding(kind, project, field string) model.Finding {
+	return model.Finding{
+		Kind:        kind,
+		Category:    model.KindCategories[kind],
+		Severity:    model.SeverityError,
+		Project:     project,
+		Field:       field,
+		Message:     "error: " + project + " " + field,
+		Fingerprint: model.Fingerprint(kind, project, field),
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	alpha := historyFinding(model.KindRepo, "alpha", "repo")
+	beta := historyFinding(model.KindJoin, "beta", "join")
+	gamma := historyFinding(model.KindStatus, "gamma", "status")
+	input := historyFinding(model.KindInput, "", "")
+	day := func(n int) time.Time {
+		return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, n).Add(3 * time.Hour)
+	}
+	for _, rep := range []*model.Report{
+		{Started: day(-3), Findings: []model.Finding{alpha, beta}},
+		{Started: day(-2), Findings: []model.Finding{alpha, beta}},
+		// Input errors: the run is stored, findings are not updated
+		{Started: day(-1), Findings: []model.Finding{input}, Error: "reading inputs"},
+		{Started: day(0), Findings: []model.Finding{beta, gamma}, Fetches: []model.FetchStat{{Source: model.SourceLandscape, Path: "landscape.yml"}}},
+	} {
+		rep.Finished = rep.Started.Add(time.Minute)
+		err := RecordHistory(path, rep)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, findings, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 4 || !runs[0].Started.Equal(day(-3)) || runs[2].Error != "reading inputs" || runs[2].Counts[model.CatInput] != 1 {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+	if len(runs[3].Fingerprints) != 2 || len(runs[3].Inputs) != 1 || runs[3].Inputs[0].Path != "landscape.yml" {
+		t.Errorf("unexpected last run: %+v", runs[3])
+	}
+	got := make(map[string]FindingRecord)
+	for _, rec := range findings {
+		got[rec.Project] = rec
+	}
+	for _, tc := range []struct {
+		project     string
+		first, last time.Time
+		runs        int
+	}{
+		{project: "alpha", first: day(-3), last: day(-2), runs: 2},
+		{project: "beta", first: day(-3), last: day(0), runs: 3},
+		{project: "gamma", first: day(0), last: day(0), runs: 1},
+	} {
+		rec, ok := got[tc.project]
+		if !ok || !rec.FirstSeen.Equal(tc.first) || !rec.LastSeen.Equal(tc.last) || rec.Runs != tc.runs || rec.Message == "" {
+			t.Errorf("%s: got %+v, want first seen %v, last seen %v, %d runs", tc.project, rec, tc.first, tc.last, tc.runs)
+		}
+	}
+	if len(findings) != 3 {
+		t.Errorf("got %d findings, want 3 (input errors are not recorded)", len(findings))
+	}
+	// Trend skips runs with input errors, alpha is resolved as the last successful run did not report it
+	for _, tc := range []struct {
+		name    string
+		days    int
+		project string
+		open    bool
+		want    []string
+		notWant []string
+	}{
+		{name: "all", days: 30, want: []string{day(-3).Format("2006-01-02"), day(0).Format("2006-01-02"), "error: alpha repo", "resolved", "error: gamma status"}, notWant: []string{day(-1).Format("2006-01-02")}},
+		{name: "last day", days: 1, want: []string{day(0).Format("2006-01-02")}, notWant: []string{"\n" + day(-2).Format("2006-01-02")}},
+		{name: "project", days: 30, project: "BETA", want: []string{"error: beta join", "open"}, notWant: []string{"alpha", "gamma"}},
+		{name: "open", days: 30, open: true, want: []string{"error: beta join", "error: gamma status"}, notWant: []string{"alpha", "resolved"}},
+	} {
+		out := &bytes.Buffer{}
+		err = PrintHistory(out, path, tc.days, tc.project, tc.open)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: output does not contain '%s':\n%s", tc.name, want, out.String())
+			}
+		}
+		for _, notWant := range tc.notWant {
+			if strings.Contains(out.String(), notWant) {
+				t.Errorf("%s: output contains '%s':\n%s", tc.name, notWant, out.String())
+			}
+		}
+	}
+	_, _, err = LoadHistory(filepath.Join(t.TempDir(), "missing", "history.db"))
+	if err == nil {
+		t.Errorf("missing history database loaded")
+	}
+}
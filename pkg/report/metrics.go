@@ -0,0 +1,186 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// Metrics holds sync health metrics exposed in Prometheus text format
+// Failures and last success time are kept across runs (in memory for daemon mode, in the textfile otherwise)
+type Metrics struct {
+	mu            sync.Mutex
+	last          *model.Report
+	lastSuccess   time.Time
+	fetchFailures map[string]float64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{fetchFailures: make(map[string]float64)}
+}
+
+// Update records a finished sync check
+func (m *Metrics) Update(rep *model.Report) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.last = rep
+	if rep.Error == "" {
+		m.lastSuccess = rep.Finished
+	}
+	for _, fetch := range rep.Fetches {
+		if fetch.Failed {
+			m.fetchFailures[fetch.Source]++
+		}
+	}
+}
+
+// sortedKeys returns keys of a given map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Write outputs all metrics in Prometheus text exposition format
+func (m *Metrics) Write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+	if m.last != nil {
+		counts := m.last.CategoryCounts()
+		metric("check_sync_findings", "gauge", "Number of findings (mismatches) per category in the last run.")
+		for _, category := range model.Categories {
+			fmt.Fprintf(w, "check_sync_findings{category=\"%s\"} %d\n", category, counts[category])
+		}
+		metric("check_sync_findings_by_severity", "gauge", "Number of findings per severity in the last run.")
+		severities := model.SeverityCounts(m.last.Findings)
+		for _, severity := range model.Severities[:3] {
+			fmt.Fprintf(w, "check_sync_findings_by_severity{severity=\"%s\"} %d\n", severity, severities[severity])
+		}
+		metric("check_sync_new_findings", "gauge", "Number of findings not reported by the previous run.")
+		fmt.Fprintf(w, "check_sync_new_findings %d\n", len(m.last.New))
+		metric("check_sync_projects", "gauge", "Number of projects per maturity level in a given source.")
+		for _, source := range sortedKeys(m.last.StatusCounts) {
+			statuses := []string{}
+			for status := range m.last.StatusCounts[source] {
+				statuses = append(statuses, status)
+			}
+			model.SortStatuses(statuses)
+			for _, status := range statuses {
+				fmt.Fprintf(w, "check_sync_projects{source=\"%s\",status=\"%s\"} %d\n", source, status, m.last.StatusCounts[source][status])
+			}
+		}
+		metric("check_sync_fetch_duration_seconds", "gauge", "How long fetching a given source took in the last run.")
+		for _, fetch := range m.last.Fetches {
+			fmt.Fprintf(w, "check_sync_fetch_duration_seconds{source=\"%s\"} %g\n", fetch.Source, fetch.Duration.Seconds())
+		}
+		metric("check_sync_last_run_timestamp_seconds", "gauge", "When the last run finished.")
+		fmt.Fprintf(w, "check_sync_last_run_timestamp_seconds %d\n", m.last.Finished.Unix())
+		metric("check_sync_last_run_duration_seconds", "gauge", "How long the last run took.")
+		fmt.Fprintf(w, "check_sync_last_run_duration_seconds %g\n", m.last.Finished.Sub(m.last.Started).Seconds())
+	}
+	metric("check_sync_fetch_failures_total", "counter", "Number of failed fetches of a given source.")
+	failures := make(map[string]float64)
+	for _, source := range model.Sources {
+		failures[source] = 0
+	}
+	for source, n := range m.fetchFailures {
+		failures[source] = n
+	}
+	for _, source := range sortedKeys(failures) {
+		fmt.Fprintf(w, "check_sync_fetch_failures_total{source=\"%s\"} %g\n", source, failures[source])
+	}
+	if !m.lastSuccess.IsZero() {
+		metric("check_sync_last_success_timestamp_seconds", "gauge", "When the last successful run finished.")
+		fmt.Fprintf(w, "check_sync_last_success_timestamp_seconds %d\n", m.lastSuccess.Unix())
+	}
+}
+
+// LoadTextfile restores counters and last success time from a previously written textfile
+func (m *Metrics) LoadTextfile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		ary := strings.Fields(line)
+		if len(ary) != 2 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		value, err := strconv.ParseFloat(ary[1], 64)
+		if err != nil {
+			continue
+		}
+		if ary[0] == "check_sync_last_success_timestamp_seconds" {
+			m.lastSuccess = time.Unix(int64(value), 0)
+			continue
+		}
+		prefix := "check_sync_fetch_failures_total{source=\""
+		if strings.HasPrefix(ary[0], prefix) {
+			m.fetchFailures[strings.TrimSuffix(strings.TrimPrefix(ary[0], prefix), "\"}")] = value
+		}
+	}
+	return scanner.Err()
+}
+
+// WriteTextfile writes metrics for node_exporter's textfile collector, file is replaced atomically
+func (m *Metrics) WriteTextfile(path string) error {
+	var buf bytes.Buffer
+	m.Write(&buf)
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".check_sync_metrics")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// UpdateTextfile records a run in a given textfile, nothing is done for an empty path
+func UpdateTextfile(path string, rep *model.Report) error {
+	if path == "" || rep == nil {
+		return nil
+	}
+	m := NewMetrics()
+	err := m.LoadTextfile(path)
+	if err != nil {
+		return err
+	}
+	m.Update(rep)
+	return m.WriteTextfile(path)
+}
@@ -0,0 +1,55 @@
+package report_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+)
+
+func TestMetricsFetchFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "check_sync.prom")
+	now := time.Now()
+	rep := &model.Report{
+		Started:  now,
+		Finished: now,
+		Findings: []model.Finding{},
+		Fetches: []model.FetchStat{
+			{Source: model.SourceLandscape},
+			{Source: model.SourceWebsite, Failed: true},
+			{Source: model.SourceAllRepoGroups, Failed: true},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		err := report.UpdateTextfile(path, rep)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := report.NewMetrics()
+	err := m.LoadTextfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	m.Write(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		`check_sync_fetch_failures_total{source="all-repo-groups"} 2`,
+		`check_sync_fetch_failures_total{source="website"} 2`,
+		`check_sync_fetch_failures_total{source="landscape"} 0`,
+		`check_sync_fetch_failures_total{source="maintainers"} 0`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing '%s' in:\n%s", want, out)
+		}
+	}
+	// Sources are written in a stable (alphabetical) order
+	if strings.Index(out, `source="all-repo-groups"`) > strings.Index(out, `source="website"`) {
+		t.Errorf("fetch failures not sorted:\n%s", out)
+	}
+}
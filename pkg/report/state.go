@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// LoadFingerprints returns fingerprints reported by the previous run, nil if there was no previous run
+func LoadFingerprints(path string) (map[string]struct{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fingerprints []string
+	err = json.Unmarshal(data, &fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	prev := make(map[string]struct{})
+	for _, fp := range fingerprints {
+		prev[fp] = struct{}{}
+	}
+	return prev, nil
+}
+
+// SaveFingerprints stores fingerprints of current findings, to be compared with by the next run
+func SaveFingerprints(path string, findings []model.Finding) error {
+	fingerprints := []string{}
+	for _, f := range findings {
+		fingerprints = append(fingerprints, f.Fingerprint)
+	}
+	data, err := json.MarshalIndent(fingerprints, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// NewFindings returns findings not present in previous fingerprints (all when there was no previous run)
+func NewFindings(findings []model.Finding, prev map[string]struct{}) []model.Finding {
+	if prev == nil {
+		return findings
+	}
+	fresh := []model.Finding{}
+	for _, f := range findings {
+		_, ok := prev[f.Fingerprint]
+		if !ok {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh
+}
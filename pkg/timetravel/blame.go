@@ -0,0 +1,143 @@
+package timetravel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// blamedCategories are categories of findings compared between landscape and devstats sources, only they are blamed:
+// optional sources are not read from local clones, so their findings cannot be bisected
+var blamedCategories = map[string]struct{}{
+	model.CatDocker:           {},
+	model.CatMissingDevstats:  {},
+	model.CatMissingLandscape: {},
+	model.CatRepo:             {},
+	model.CatJoin:             {},
+	model.CatIncubating:       {},
+	model.CatGraduated:        {},
+	model.CatStatus:           {},
+}
+
+// blamer bisects history of all sources, checks at each event are cached as they are shared between findings
+type blamer struct {
+	checker *compare.Checker
+	sources map[string]*loader.GitSource
+	events  []sourceEvent
+	checked map[int]map[string]model.Finding
+}
+
+// newBlamer returns nil when local clones are not configured, pull updates them first
+func newBlamer(c *compare.Checker, clones loader.Clones, pull bool) (*blamer, error) {
+	if len(clones.Dirs()) == 0 {
+		return nil, nil
+	}
+	if pull {
+		for _, dir := range clones.Dirs() {
+			_, err := loader.GitOutput(dir, "pull", "-q", "--ff-only")
+			if err != nil {
+				return nil, fmt.Errorf("git pull in '%s': %v", dir, err)
+			}
+		}
+	}
+	sources, err := loader.GitSources(clones)
+	if err != nil {
+		return nil, err
+	}
+	return &blamer{
+		checker: c,
+		sources: sources,
+		events:  sourceEvents(sources, time.Time{}, time.Now()),
+		checked: make(map[int]map[string]model.Finding),
+	}, nil
+}
+
+// present returns whether a given finding is reported after a given event, nothing exists before the first one
+// Checks that fail (for example invalid YAML at that commit) are treated as not reporting anything
+func (b *blamer) present(i int, fp string) bool {
+	if i < 0 {
+		return false
+	}
+	current, ok := b.checked[i]
+	if !ok {
+		current, _ = findingsAt(b.checker, b.sources, b.events[i].commit.Time)
+		b.checked[i] = current
+	}
+	_, ok = current[fp]
+	return ok
+}
+
+// blame finds the event after which a finding is reported until now, nil if local clones don't report it
+func (b *blamer) blame(fp string) *model.Blame {
+	hi := len(b.events) - 1
+	if hi < 0 || !b.present(hi, fp) {
+		return nil
+	}
+	// Findings can be fixed and reintroduced, so search backwards from the newest event
+	// in growing steps for one not reporting the finding, then bisect between the two
+	lo, step := hi-1, 1
+	for lo >= 0 && b.present(lo, fp) {
+		hi = lo
+		step *= 2
+		lo = hi - step
+	}
+	if lo < -1 {
+		lo = -1
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if b.present(mid, fp) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	// Commits done at the same second are checked together, report the last of them
+	for hi+1 < len(b.events) && b.events[hi+1].commit.Time.Equal(b.events[hi].commit.Time) {
+		hi++
+	}
+	event := b.events[hi]
+	return &model.Blame{
+		File:   event.g.File,
+		SHA:    event.commit.SHA,
+		Author: event.commit.Author,
+		Time:   event.commit.Time.UTC().Format("2006-01-02 15:04"),
+		PR:     event.commit.PR,
+		Title:  event.commit.Title,
+	}
+}
+
+// BlameNew sets blame of new findings (in both new and all findings) and returns lines for the output
+// Nothing is done when local clones are not configured, pull updates them first
+// Blame only covers findings comparing landscape and devstats sources, see blamedCategories
+func BlameNew(c *compare.Checker, clones loader.Clones, pull bool, rep *model.Report) ([]string, error) {
+	if len(rep.New) == 0 {
+		return nil, nil
+	}
+	b, err := newBlamer(c, clones, pull)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	blames := make(map[string]*model.Blame)
+	lines := []string{}
+	for i, f := range rep.New {
+		_, ok := blamedCategories[f.Category]
+		if !ok {
+			continue
+		}
+		rep.New[i].Blame = b.blame(f.Fingerprint)
+		if rep.New[i].Blame == nil {
+			lines = append(lines, fmt.Sprintf("blame: %s: not reported for local clones, are they up to date?\n", f.Message))
+			continue
+		}
+		blames[f.Fingerprint] = rep.New[i].Blame
+		lines = append(lines, fmt.Sprintf("blame: %s: %s\n", f.Message, rep.New[i].Blame))
+	}
+	for i, f := range rep.Findings {
+		rep.Findings[i].Blame = blames[f.Fingerprint]
+	}
+	return lines, nil
+}
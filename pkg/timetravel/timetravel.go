@@ -0,0 +1,168 @@
+// Package timetravel replays sync checks on historical inputs read from local git clones of upstream
+// repositories: at a given date, over a date range, or to find the commit that introduced a finding.
+package timetravel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+)
+
+// gitPaths are used to name inputs read from git clones
+var gitPaths = loader.Paths{Landscape: loader.LandscapeFile, Projects: loader.ProjectsFile, Projects2: loader.Projects2File}
+
+// ParseDay parses YYYY-MM-DD date and returns the end of that day (UTC)
+func ParseDay(day string) (time.Time, error) {
+	dt, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return dt, fmt.Errorf("invalid date '%s', expected YYYY-MM-DD", day)
+	}
+	return dt.Add(24*time.Hour - time.Second), nil
+}
+
+// CheckAt runs the full check on inputs of given local clones as they were at the end of a given day and prints its output
+func CheckAt(c *compare.Checker, clones loader.Clones, day string) error {
+	t, err := ParseDay(day)
+	if err != nil {
+		return err
+	}
+	sources, err := loader.GitSources(clones)
+	if err != nil {
+		return err
+	}
+	for _, source := range []string{model.SourceLandscape, model.SourceDevstats, model.SourceDevstatsHelm} {
+		g := sources[source]
+		commit := g.At(t)
+		if commit != nil {
+			fmt.Printf("%s at %s\n", g.File, commit)
+		}
+	}
+	r, err := c.Check(loader.ReadAt(sources, t), gitPaths)
+	if r.Report {
+		for _, msg := range r.Msgs {
+			fmt.Printf("%s", msg)
+		}
+	}
+	return err
+}
+
+// sourceEvent is a commit changing one of the sources
+type sourceEvent struct {
+	g      *loader.GitSource
+	commit loader.GitCommit
+}
+
+// sourceEvents returns commits changing any of the sources between from and to, oldest first
+func sourceEvents(sources map[string]*loader.GitSource, from, to time.Time) []sourceEvent {
+	events := []sourceEvent{}
+	for _, g := range sources {
+		for _, commit := range g.Commits {
+			if !commit.Time.Before(from) && !commit.Time.After(to) {
+				events = append(events, sourceEvent{g: g, commit: commit})
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].commit.Time.Before(events[j].commit.Time) })
+	return events
+}
+
+// findingsAt runs the check quietly on inputs as they were at a given time and returns findings by fingerprint
+func findingsAt(c *compare.Checker, sources map[string]*loader.GitSource, t time.Time) (map[string]model.Finding, error) {
+	r, err := c.Check(loader.ReadAt(sources, t), gitPaths)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]model.Finding)
+	for _, f := range r.Findings {
+		current[f.Fingerprint] = f
+	}
+	return current, nil
+}
+
+// CheckRange replays the check across all commits of given local clones changing any of the inputs in a given date range
+// and reports when each finding was introduced and when it was fixed
+func CheckRange(c *compare.Checker, clones loader.Clones, fromDay, toDay string) error {
+	to, err := ParseDay(toDay)
+	if err != nil {
+		return err
+	}
+	from, err := ParseDay(fromDay)
+	if err != nil {
+		return err
+	}
+	from = from.Add(-24*time.Hour + time.Second)
+	if from.After(to) {
+		return fmt.Errorf("from date %s is after to date %s", fromDay, toDay)
+	}
+	sources, err := loader.GitSources(clones)
+	if err != nil {
+		return err
+	}
+	events := sourceEvents(sources, from, to)
+	prev, err := findingsAt(c, sources, from.Add(-time.Second))
+	if err != nil {
+		return fmt.Errorf("baseline at %s: %v", from.Format("2006-01-02"), err)
+	}
+	fmt.Printf("baseline at %s: %d findings\n", fromDay, len(prev))
+	// introduced/fixed hold the commit that introduced/fixed each finding
+	introduced := make(map[string]string)
+	fixed := make(map[string]string)
+	all := make(map[string]model.Finding)
+	for fp, f := range prev {
+		all[fp] = f
+		introduced[fp] = "before " + fromDay
+	}
+	for i, event := range events {
+		// Commits done at the same second are checked together
+		if i+1 < len(events) && events[i+1].commit.Time.Equal(event.commit.Time) {
+			continue
+		}
+		current, err := findingsAt(c, sources, event.commit.Time)
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", event.g.File, event.commit.String(), err)
+			continue
+		}
+		where := fmt.Sprintf("%s %s", event.g.File, event.commit.String())
+		changes := []string{}
+		for fp, f := range current {
+			_, ok := prev[fp]
+			if !ok {
+				changes = append(changes, "  + "+f.Message)
+				all[fp] = f
+				introduced[fp] = where
+				delete(fixed, fp)
+			}
+		}
+		for fp, f := range prev {
+			_, ok := current[fp]
+			if !ok {
+				changes = append(changes, "  - "+f.Message)
+				fixed[fp] = where
+			}
+		}
+		if len(changes) > 0 {
+			sort.Strings(changes)
+			fmt.Printf("%s\n%s\n", where, strings.Join(changes, "\n"))
+		}
+		prev = current
+	}
+	fps := []string{}
+	for fp := range all {
+		fps = append(fps, fp)
+	}
+	sort.Slice(fps, func(i, j int) bool { return all[fps[i]].Message < all[fps[j]].Message })
+	fmt.Printf("\nsummary (%d events, %d findings):\n", len(events), len(fps))
+	for _, fp := range fps {
+		fixedAt, ok := fixed[fp]
+		if !ok {
+			fixedAt = "still present"
+		}
+		fmt.Printf("%s\n  introduced: %s\n  fixed: %s\n", all[fp].Message, introduced[fp], fixedAt)
+	}
+	return nil
+}
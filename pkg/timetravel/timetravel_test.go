@@ -0,0 +1,231 @@
+package timetravel
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/compare"
+	"github.com/cncf/devstats-landscape-sync/pkg/loader"
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/normalize"
+)
+
+// fixtures holds in sync inputs shared with the compare package tests
+var fixtures = filepath.Join("..", "compare", "testdata", "in-sync")
+
+// git runs a git command in a given directory, commits are made at a given time
+func git(t *testing.T, dir string, at time.Time, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	date := at.UTC().Format(time.RFC3339)
+	cmd.Env = append(
+		os.Environ(),
+		"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE="+date,
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes a file in a clone and commits it with a given message, returns commit SHA
+func commit(t *testing.T, dir, file string, data []byte, at time.Time, msg string) string {
+	path := filepath.Join(dir, file)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, at, "add", file)
+	git(t, dir, at, "commit", "-q", "-m", msg)
+	return git(t, dir, at, "rev-parse", "HEAD")
+}
+
+// fixture returns contents of an in sync input, with given replacements
+func fixture(t *testing.T, name string, oldnew ...string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join(fixtures, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(strings.NewReplacer(oldnew...).Replace(string(data)))
+}
+
+// testHistory holds local clones and commits made in them
+type testHistory struct {
+	clones loader.Clones
+	// introduced is the landscape commit changing alpha's repo
+	introduced string
+}
+
+// newTestHistory creates local clones: all inputs in sync on 2024-01-01, devstats is broken on 2024-01-03 and restored
+// on 2024-01-04, landscape changes alpha's repo on 2024-01-05 (squash merged PR #42), then unrelated changes are made
+// on 2024-01-10 and 2024-01-12
+func newTestHistory(t *testing.T) *testHistory {
+	h := &testHistory{clones: loader.Clones{Landscape: t.TempDir(), Devstats: t.TempDir(), DevstatsDockerImages: t.TempDir()}}
+	for _, dir := range h.clones.Dirs() {
+		git(t, dir, time.Now(), "init", "-q")
+	}
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+	commit(t, h.clones.Landscape, loader.LandscapeFile, fixture(t, "landscape.yml"), day(1), "Initial landscape")
+	commit(t, h.clones.Devstats, loader.ProjectsFile, fixture(t, "projects.yaml"), day(1), "Initial projects")
+	commit(t, h.clones.DevstatsDockerImages, loader.Projects2File, fixture(t, "helm-projects.yaml"), day(1), "Initial helm projects")
+	commit(t, h.clones.Devstats, loader.ProjectsFile, []byte("projects: [\n"), day(3), "Break projects")
+	commit(t, h.clones.Devstats, loader.ProjectsFile, fixture(t, "projects.yaml"), day(4), "Restore projects")
+	moved := "https://github.com/alpha/alpha-moved"
+	h.introduced = commit(t, h.clones.Landscape, loader.LandscapeFile, fixture(t, "landscape.yml", "https://github.com/alpha/alpha", moved), day(5), "Move alpha repo (#42)")
+	commit(t, h.clones.Landscape, loader.LandscapeFile, append(fixture(t, "landscape.yml", "https://github.com/alpha/alpha", moved), "# comment\n"...), day(10), "Add a comment")
+	commit(t, h.clones.Landscape, loader.LandscapeFile, append(fixture(t, "landscape.yml", "https://github.com/alpha/alpha", moved), "# comments\n"...), day(12), "Change a comment")
+	return h
+}
+
+// testChecker returns checker using exceptions the in sync fixture needs
+func testChecker(t *testing.T) *compare.Checker {
+	store := &normalize.Store{}
+	for _, e := range []struct {
+		typ string
+		e   normalize.Exception
+	}{
+		{normalize.TypeSkip, normalize.Exception{Project: "all", Reason: "not a single project"}},
+		{normalize.TypeRename, normalize.Exception{Project: "renamed devstats", Landscape: "renamed landscape", Reason: "renamed"}},
+	} {
+		_, err := store.Put(e.typ, e.e)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &compare.Checker{Exceptions: store.Exceptions(), Policy: model.DefaultPolicy()}
+}
+
+// stdout returns what a given function prints
+func stdout(t *testing.T, fn func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		done <- data
+	}()
+	err = fn()
+	os.Stdout = saved
+	_ = w.Close()
+	return string(<-done), err
+}
+
+func TestCheckAt(t *testing.T) {
+	h := newTestHistory(t)
+	c := testChecker(t)
+	for _, tc := range []struct {
+		day  string
+		want []string
+		err  string
+	}{
+		{day: "2024-01-02", want: []string{"landscape.yml at ", "Initial landscape", "projects.yaml at ", "Initial helm projects"}},
+		{day: "2024-01-05", want: []string{"PR #42 Move alpha repo", "alpha/alpha-moved", "Jane Doe <jane@example.com>"}},
+		{day: "2024-01-03", want: []string{"Break projects"}, err: "yaml"},
+		{day: "2023-12-31", err: "didn't exist"},
+		{day: "2024-13-01", err: "invalid date"},
+	} {
+		out, err := stdout(t, func() error { return CheckAt(c, h.clones, tc.day) })
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: got error %v, want '%s'", tc.day, err, tc.err)
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: output does not contain '%s':\n%s", tc.day, want, out)
+			}
+		}
+		if tc.day == "2024-01-02" && strings.Contains(out, "error") {
+			t.Errorf("%s: inputs in sync reported errors:\n%s", tc.day, out)
+		}
+	}
+	_, err := stdout(t, func() error { return CheckAt(c, loader.Clones{Landscape: h.clones.Landscape}, "2024-01-02") })
+	if err == nil || !strings.Contains(err.Error(), "-devstats-repo") {
+		t.Errorf("got error %v, want missing devstats clone", err)
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	h := newTestHistory(t)
+	out, err := stdout(t, func() error { return CheckRange(testChecker(t), h.clones, "2024-01-02", "2024-01-31") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"baseline at 2024-01-02: 0 findings", "PR #42 Move alpha repo\n  + ", "summary (5 events, 1 findings)", "Break projects: ", "fixed: still present"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain '%s':\n%s", want, out)
+		}
+	}
+	_, err = stdout(t, func() error { return CheckRange(testChecker(t), h.clones, "2024-01-31", "2024-01-03") })
+	if err == nil {
+		t.Errorf("reversed range checked")
+	}
+}
+
+// TestBlame makes sure bisection finds the commit introducing a finding, not later unrelated commits
+// or earlier ones where the check fails
+func TestBlame(t *testing.T) {
+	h := newTestHistory(t)
+	c := testChecker(t)
+	current, err := findingsAt(c, mustSources(t, h.clones), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := &model.Report{}
+	for _, f := range current {
+		rep.Findings = append(rep.Findings, f)
+	}
+	if len(rep.Findings) != 1 || rep.Findings[0].Project != "alpha" || rep.Findings[0].Field != "repo" {
+		t.Fatalf("unexpected findings: %+v", rep.Findings)
+	}
+	rep.New = append([]model.Finding{}, rep.Findings...)
+	lines, err := BlameNew(c, h.clones, false, rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := rep.New[0].Blame
+	if b == nil || b.SHA != h.introduced || b.PR != 42 || b.Title != "Move alpha repo" || b.File != loader.LandscapeFile || b.Time != "2024-01-05 12:00" {
+		t.Fatalf("got blame %+v, want commit %s", b, h.introduced)
+	}
+	if rep.Findings[0].Blame != b || len(lines) != 1 || !strings.Contains(lines[0], model.ShortSHA(h.introduced)) {
+		t.Errorf("blame not reported: %+v, %q", rep.Findings[0].Blame, lines)
+	}
+	// Findings not reported for local clones are not blamed, findings of optional sources are not even looked for
+	rep = &model.Report{New: []model.Finding{
+		model.NewFinding(model.KindJoin, model.SeverityError, "beta", "join", "beta join"),
+		model.NewFinding(model.KindCLOMonitor, model.SeverityError, "alpha", "status", "alpha clomonitor status"),
+	}}
+	lines, err = BlameNew(c, h.clones, false, rep)
+	if err != nil || rep.New[0].Blame != nil || rep.New[1].Blame != nil || len(lines) != 1 || !strings.Contains(lines[0], "beta join: not reported for local clones") {
+		t.Errorf("got %q, %v, blame %+v, %+v", lines, err, rep.New[0].Blame, rep.New[1].Blame)
+	}
+	// Nothing is done without local clones
+	lines, err = BlameNew(c, loader.Clones{}, false, rep)
+	if err != nil || lines != nil {
+		t.Errorf("got %q, %v without local clones", lines, err)
+	}
+}
+
+// mustSources returns git sources of given clones
+func mustSources(t *testing.T, clones loader.Clones) map[string]*loader.GitSource {
+	sources, err := loader.GitSources(clones)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sources
+}
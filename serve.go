@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/model"
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+	"github.com/robfig/cron/v3"
+)
+
+// syncServer runs sync checks on a schedule and serves the latest report
+type syncServer struct {
+	opts    *checkOptions
+	mu      sync.RWMutex
+	last    *model.Report
+	running int32
+	metrics *report.Metrics
+}
+
+// runCheck runs a sync check unless one is already running, returns false in that case
+func (s *syncServer) runCheck() bool {
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		fmt.Printf("check already in progress, skipping\n")
+		return false
+	}
+	go func() {
+		defer atomic.StoreInt32(&s.running, 0)
+		rep, _ := checkSync(s.opts)
+		s.mu.Lock()
+		s.last = rep
+		s.mu.Unlock()
+		s.metrics.Update(rep)
+		recordRun(s.opts, rep)
+	}()
+	return true
+}
+
+// statusJSON is the JSON document served at /status
+type statusJSON struct {
+	*model.Report
+	Running    bool           `json:"running"`
+	Counts     map[string]int `json:"counts"`
+	Severities map[string]int `json:"severities"`
+	Total      int            `json:"total"`
+}
+
+func (s *syncServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	last := s.last
+	s.mu.RUnlock()
+	running := atomic.LoadInt32(&s.running) == 1
+	if last == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "no check finished yet, running: %v\n", running)
+		return
+	}
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statusJSON{Report: last, Running: running, Counts: last.CategoryCounts(), Severities: model.SeverityCounts(last.Findings), Total: len(last.Findings)})
+		return
+	}
+	title := fmt.Sprintf("%s at %s", report.Subject(last.Findings), last.Finished.Format(time.RFC3339))
+	if last.Error != "" {
+		title += ", error: " + last.Error
+	}
+	if running {
+		title += " (check in progress)"
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := report.Template.Execute(w, report.Data{Title: title, Groups: model.GroupFindings(last.Findings), Total: len(last.Findings), Log: strings.Join(last.Msgs, "")})
+	if err != nil {
+		fmt.Printf("error: rendering status: %v\n", err)
+	}
+}
+
+func (s *syncServer) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.runCheck() {
+		http.Error(w, "check already in progress", http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "check started\n")
+}
+
+func (s *syncServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.metrics.Write(w)
+}
+
+// handler returns handler of all endpoints
+func (s *syncServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/check", s.handleCheck)
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "ok\n")
+}
+
+// serve implements the serve command: runs checks as a daemon on a schedule
+// Endpoints: /status (HTML, JSON with ?format=json or Accept: application/json), /healthz, /metrics, POST /check
+func serve(args []string) error {
+	o := &checkOptions{}
+	fs := newFlagSet("serve", "")
+	o.register(fs)
+	schedule := fs.String("schedule", envOr("SCHEDULE", "0 3 * * *"), "cron expression of scheduled checks (SCHEDULE)")
+	addr := fs.String("addr", envOr("SERVE_ADDR", ":8080"), "listen address (SERVE_ADDR)")
+	err := parseFlags(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	s := &syncServer{opts: o, metrics: report.NewMetrics()}
+	scheduler := cron.New()
+	_, err = scheduler.AddFunc(*schedule, func() { s.runCheck() })
+	if err != nil {
+		return usageError{fmt.Errorf("invalid schedule '%s': %v", *schedule, err)}
+	}
+	scheduler.Start()
+	defer scheduler.Stop()
+	s.runCheck()
+	fmt.Printf("serving on %s, checks scheduled at '%s'\n", *addr, *schedule)
+	return http.ListenAndServe(*addr, s.handler())
+}
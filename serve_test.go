@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cncf/devstats-landscape-sync/pkg/report"
+)
+
+// testServer returns a server checking the compare package "exceptions" fixture, without notifiers
+func testServer(t *testing.T) (*syncServer, *httptest.Server) {
+	clearEnv(t)
+	_, inputs := testInputs(t)
+	o := &checkOptions{}
+	fs := newFlagSet("serve", "")
+	o.register(fs)
+	err := parseFlags(fs, append([]string{"-state", filepath.Join(t.TempDir(), "state.json"), "-notifiers", ""}, inputs...), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &syncServer{opts: o, metrics: report.NewMetrics()}
+	server := httptest.NewServer(s.handler())
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+// get returns status code, content type and body of a GET request
+func get(t *testing.T, url string, accept string) (int, string, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, response.Header.Get("Content-Type"), string(body)
+}
+
+// waitIdle waits until a running check finishes
+func waitIdle(t *testing.T, s *syncServer) {
+	for i := 0; atomic.LoadInt32(&s.running) == 1; i++ {
+		if i > 500 {
+			t.Fatal("check did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServe(t *testing.T) {
+	s, server := testServer(t)
+	code, _, body := get(t, server.URL+"/status", "")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "no check finished yet") {
+		t.Errorf("status before first check: %d %s", code, body)
+	}
+	code, _, body = get(t, server.URL+"/healthz", "")
+	if code != http.StatusOK || body != "ok\n" {
+		t.Errorf("healthz: %d %s", code, body)
+	}
+	// Only POST starts a check
+	code, _, _ = get(t, server.URL+"/check", "")
+	if code != http.StatusMethodNotAllowed || atomic.LoadInt32(&s.running) != 0 {
+		t.Errorf("GET /check: got %d", code)
+	}
+	response, err := http.Post(server.URL+"/check", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		t.Errorf("POST /check: got %d", response.StatusCode)
+	}
+	waitIdle(t, s)
+	code, contentType, body := get(t, server.URL+"/status?format=json", "")
+	var status struct {
+		Running  bool                     `json:"running"`
+		Total    int                      `json:"total"`
+		Findings []map[string]interface{} `json:"findings"`
+	}
+	err = json.Unmarshal([]byte(body), &status)
+	if code != http.StatusOK || contentType != "application/json" || err != nil {
+		t.Fatalf("status JSON: %d %s %v\n%s", code, contentType, err, body)
+	}
+	if status.Running || status.Total != 4 || len(status.Findings) != 4 || status.Findings[0]["project"] != "outdated" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	code, contentType, _ = get(t, server.URL+"/status", "application/json")
+	if code != http.StatusOK || contentType != "application/json" {
+		t.Errorf("status with Accept: application/json: %d %s", code, contentType)
+	}
+	code, contentType, body = get(t, server.URL+"/status", "text/html")
+	if code != http.StatusOK || !strings.HasPrefix(contentType, "text/html") || !strings.Contains(body, "<html") || !strings.Contains(body, "ignored devstats repo is incorrect") {
+		t.Errorf("status HTML: %d %s\n%s", code, contentType, body)
+	}
+	code, _, body = get(t, server.URL+"/metrics", "")
+	if code != http.StatusOK || !strings.Contains(body, "check_sync_") {
+		t.Errorf("metrics: %d\n%s", code, body)
+	}
+}
+
+// TestServeOverlappingChecks makes sure a check is not started while another one is running
+func TestServeOverlappingChecks(t *testing.T) {
+	s, server := testServer(t)
+	atomic.StoreInt32(&s.running, 1)
+	response, err := http.Post(server.URL+"/check", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusConflict {
+		t.Errorf("POST /check during a check: got %d", response.StatusCode)
+	}
+	if s.runCheck() {
+		t.Errorf("scheduled check started during a check")
+	}
+	atomic.StoreInt32(&s.running, 0)
+	if !s.runCheck() {
+		t.Errorf("check not started when idle")
+	}
+	waitIdle(t, s)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.last == nil || s.last.Finished.IsZero() {
+		t.Errorf("check did not record its report: %+v", s.last)
+	}
+}